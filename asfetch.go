@@ -2,290 +2,194 @@ package main
 
 import (
 	"asf/config"
-	"asf/desktop"
-	"asf/dodatki"
-	"asf/hardware"
+	"asf/fetch"
+	"asf/graphics"
+	"asf/logo"
 	"asf/osinfo"
+	"asf/system"
+	"asf/term"
+	"asf/theme"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 )
 
-// KOLORY - zmienione na kolory ANSI, które są częścią standardowej palety terminala
-const (
-	ColorLabelLight = "\033[94m" // Jasny Cyan
-	ColorLabelDark  = "\033[36m" // Ciemny Cyan
-	ColorSepLight   = "\033[90m" // Jasny niebieski
-	ColorSepDark    = "\033[97m" // Ciemny niebieski
-	ColorValueLight = "\033[96m" // Jasny biały
-	ColorValueDark  = "\033[34m" // Ciemny szary
-	ColorReset      = "\033[0m"
-)
-
 func main() {
-	cfg := config.LoadConfig()
-
-	infoPairs := []struct {
-		Label string
-		Value string
-	}{}
-
-	if cfg.EnableUserHost {
-		username, hostname := dodatki.GetUserAndHost()
-		infoPairs = append(infoPairs, struct {
-			Label string
-			Value string
-		}{"User ", username + "@" + hostname})
-	}
-
-	if cfg.EnableOSInfo {
-		infoPairs = append(infoPairs, struct {
-			Label string
-			Value string
-		}{"OS ", osinfo.GetOSInfo()})
-	}
-	if cfg.EnableKernel {
-		infoPairs = append(infoPairs, struct {
-			Label string
-			Value string
-		}{"Kernel ", osinfo.GetKernel()})
+	opts, err := parseArgs(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
 	}
-	if cfg.EnablePackages {
-		infoPairs = append(infoPairs, struct {
-			Label string
-			Value string
-		}{"Packages ", osinfo.GetPackageCount()})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "asfetch: %v\n", err)
+		os.Exit(2)
 	}
 
-	if cfg.EnableDEWM {
-		de, wm := desktop.GetDEWM()
-		if de != "" {
-			infoPairs = append(infoPairs, struct {
-				Label string
-				Value string
-			}{"DE ", de})
-		}
-		if wm != "" {
-			infoPairs = append(infoPairs, struct {
-				Label string
-				Value string
-			}{"WM ", wm})
-		}
+	if opts.version {
+		fmt.Printf("asfetch %s\n", version)
+		return
 	}
 
-	if cfg.EnableGTKTheme {
-		gtkTheme := desktop.GetGTKTheme()
-		if gtkTheme != "unknown" {
-			infoPairs = append(infoPairs, struct {
-				Label string
-				Value string
-			}{"GTK ", gtkTheme})
-		}
-	}
-	if cfg.EnableIconTheme {
-		iconTheme := desktop.GetIconTheme()
-		if iconTheme != "unknown" {
-			infoPairs = append(infoPairs, struct {
-				Label string
-				Value string
-			}{"Icons ", iconTheme})
-		}
-	}
-	if cfg.EnableFont {
-		font := desktop.GetFont()
-		if font != "unknown" {
-			infoPairs = append(infoPairs, struct {
-				Label string
-				Value string
-			}{"Font ", font})
-		}
-	}
-	if cfg.EnableShell {
-		shell := dodatki.GetShell()
-		if shell != "unknown" {
-			infoPairs = append(infoPairs, struct {
-				Label string
-				Value string
-			}{"Shell ", shell})
-		}
-	}
-	if cfg.EnableUptime {
-		uptime := dodatki.GetUptime()
-		if uptime != "unknown" {
-			infoPairs = append(infoPairs, struct {
-				Label string
-				Value string
-			}{"Uptime ", uptime})
-		}
-	}
-	if cfg.EnableBattery {
-		batteryInfo := hardware.GetBatteryInfo()
-		if batteryInfo != "N/A" {
-			infoPairs = append(infoPairs, struct {
-				Label string
-				Value string
-			}{"Battery ", batteryInfo})
-		}
+	var runner system.Runner
+	if opts.recordDir != "" {
+		runner = system.Recorder{Runner: system.ExecRunner{}, Dir: opts.recordDir}
+	} else if opts.replayDir != "" {
+		runner = system.Replayer{Dir: opts.replayDir}
 	}
 
-	if cfg.EnableCPU {
-		infoPairs = append(infoPairs, struct {
-			Label string
-			Value string
-		}{"CPU ", hardware.GetCPUInfo()})
+	var cfg config.Config
+	if opts.configPath != "" {
+		cfg = config.LoadConfigFrom(opts.configPath)
+	} else {
+		cfg = config.LoadConfig()
 	}
-	if cfg.EnableGPU {
-		gpuInfo := hardware.GetGPUInfo()
-		if gpuInfo != "Unknown GPU" && gpuInfo != "N/A" {
-			infoPairs = append(infoPairs, struct {
-				Label string
-				Value string
-			}{"GPU ", gpuInfo})
-		}
+	if err := opts.apply(&cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "asfetch: %v\n", err)
+		os.Exit(2)
 	}
 
-	if cfg.EnableRAM || cfg.EnableSwap {
-		ramInfo, swapInfo := hardware.GetMemoryAndSwapInfo()
-		if cfg.EnableRAM {
-			if ramInfo != "unknown" {
-				infoPairs = append(infoPairs, struct {
-					Label string
-					Value string
-				}{"RAM ", ramInfo})
-			}
-		}
-		if cfg.EnableSwap {
-			if swapInfo != "unknown" && swapInfo != "No Swap" {
-				infoPairs = append(infoPairs, struct {
-					Label string
-					Value string
-				}{"Swap ", swapInfo})
-			}
-		}
+	var pal theme.Palette
+	if opts.color.Enabled(os.Stdout) {
+		pal = loadPalette(cfg)
 	}
 
-	if cfg.EnableMusic {
-		musicInfo := dodatki.GetCurrentMusic()
-		if musicInfo != "Not playing" {
-			infoPairs = append(infoPairs, struct {
-				Label string
-				Value string
-			}{"Spotify ", musicInfo})
+	if opts.convert != "" {
+		if err := convertImage(cfg, opts.convert, pal); err != nil {
+			fmt.Fprintf(os.Stderr, "asfetch: %v\n", err)
+			os.Exit(1)
 		}
+		return
 	}
-	maxLabelLen := 0
-	for _, pair := range infoPairs {
-		if len(pair.Label) > maxLabelLen {
-			maxLabelLen = len(pair.Label)
+
+	cfg.Modules = slices.DeleteFunc(cfg.Modules, func(entry config.ModuleEntry) bool {
+		if entry.IsLayout() || fetch.Known(entry.Name) {
+			return false
 		}
+		fmt.Fprintf(os.Stderr, "Nieznany moduł %q w konfiguracji, pomijam.\n", entry.Name)
+		return true
+	})
+
+	report, err := fetch.Collect(context.Background(), fetch.Options{
+		Modules:        cfg.ModuleNames(),
+		Timeout:        cfg.Timeout(),
+		ModuleTimeouts: cfg.ModuleTimeouts(),
+		ModuleOptions:  cfg.ModuleOptions(),
+		Sysroot:        opts.sysroot,
+		Runner:         runner,
+		CacheDir:       cacheDir(opts),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Błąd pobierania informacji: %v\n", err)
+		os.Exit(1)
 	}
 
-	var infoLines []string
-	for i, pair := range infoPairs {
-		var labelColor, sepColor, valueColor string
-		if i%2 == 0 {
-			labelColor = ColorLabelLight
-			sepColor = ColorSepDark
-			valueColor = ColorValueLight
-		} else {
-			labelColor = ColorLabelDark
-			sepColor = ColorSepLight
-			valueColor = ColorValueDark
+	if opts.format == "json" {
+		if err := writeJSON(os.Stdout, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Błąd zapisu JSON: %v\n", err)
+			os.Exit(1)
 		}
-		alignedLabel := fmt.Sprintf("%s%-*s%s", labelColor, maxLabelLen, pair.Label, ColorReset)
-		separator := fmt.Sprintf("%s│%s", sepColor, ColorReset)
-		value := fmt.Sprintf("%s%s%s", valueColor, pair.Value, ColorReset)
-		infoLines = append(infoLines, fmt.Sprintf("%s%s %s", alignedLabel, separator, value))
+		return
 	}
 
-	var logo []string
+	infoPairs := infoPairsFromReport(cfg.Modules, report, os.Stderr)
+
+	var logoLines []string
 	if cfg.EnableLogo {
 		var err error
-		logo, err = config.LoadLogoFromFile(cfg.LogoPath)
+		if graphics.IsImageFile(cfg.LogoPath) {
+			logoLines, err = imageLogo(cfg, cfg.LogoFile(), pal)
+		} else {
+			var l logo.Logo
+			if l, err = loadLogo(cfg, opts.sysroot); err == nil {
+				logoLines = l.WithColors(cfg.LogoColors).Render(pal.Logo, pal.Enabled())
+			}
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Błąd wczytywania logo z pliku: %v. Wyłączam logo.\n", err)
-			cfg.EnableLogo = false
 		}
 	}
 
-	if !cfg.EnableLogo {
-		logo = make([]string, len(infoLines))
-		if len(infoLines) < 19 {
-			logo = make([]string, 19)
-		}
+	size, _ := term.Size(os.Stdout.Fd())
+	layout, err := layoutFromConfig(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Błąd konfiguracji układu: %v. Używam układu domyślnego.\n", err)
+		layout = defaultLayout
 	}
+	layout.Width = size.Cols
+	renderText(os.Stdout, infoPairs, logoLines, pal, layout)
+}
 
-	maxLogoWidth := 0
-	if cfg.EnableLogo {
-		for _, line := range logo {
-			lineLen := 0
-			for _, r := range line {
-				if r >= 0x1F600 && r <= 0x1F64F {
-					lineLen += 2
-				} else if r >= 0x2000 && r <= 0x206F {
-					lineLen += 1
-				} else if r >= 0x2500 && r <= 0x257F {
-					lineLen += 1
-				} else if r >= 0x2800 && r <= 0x28FF {
-					lineLen += 2
-				} else if r >= 0x0000 && r <= 0x007F {
-					lineLen += 1
-				} else {
-					lineLen += 1
-				}
-			}
-			if lineLen > maxLogoWidth {
-				maxLogoWidth = lineLen
-			}
-		}
+// loadLogo wczytuje logo wskazane w konfiguracji: plik, wbudowane logo dystrybucji (distro:NAZWA)
+// albo logo wybrane na podstawie os-release badanego systemu (auto).
+func loadLogo(cfg config.Config, sysroot string) (logo.Logo, error) {
+	if cfg.LogoPath == logo.Auto {
+		release, _ := osinfo.GetOSRelease(system.New(sysroot))
+		return logo.ForOSRelease(release), nil
 	}
-	if maxLogoWidth == 0 {
-		maxLogoWidth = 20
+	if name, ok := strings.CutPrefix(cfg.LogoPath, logo.DistroPrefix); ok {
+		return logo.Distro(name)
 	}
+	return logo.Load(cfg.LogoFile())
+}
 
-	totalHeight := len(logo)
-	if len(infoLines) > totalHeight {
-		totalHeight = len(infoLines)
+// imageLogo rysuje obrazek protokołem graficznym terminalu albo zamienia go na tekst
+// (półbloki, Braille). Bez kolorów (np. wyjście do pliku) obrazek staje się Braille'em bez kolorów.
+func imageLogo(cfg config.Config, path string, pal theme.Palette) ([]string, error) {
+	proto, err := graphics.ParseProtocol(cfg.ImageProtocol, os.Getenv)
+	if err != nil {
+		return nil, err
 	}
-
-	emptyLinesTop := 1
-	for i := 0; i < emptyLinesTop; i++ {
-		fmt.Printf("%s\n", strings.Repeat(" ", maxLogoWidth))
+	img, err := graphics.Load(path)
+	if err != nil {
+		return nil, err
 	}
+	size, _ := term.Size(os.Stdout.Fd())
+	cellW, cellH := size.CellSize()
+	return img.Logo(proto, cfg.LogoColumns(), cellW, cellH, pal.Enabled())
+}
 
-	for i := 0; i < totalHeight; i++ {
-		logoLine := ""
-		if i < len(logo) {
-			logoLine = logo[i]
-		}
-
-		infoLine := ""
-		if i < len(infoLines) {
-			infoLine = infoLines[i]
-		}
+// convertImage wypisuje obrazek zamieniony na tekst, np. żeby zapisać go jako art.txt.
+func convertImage(cfg config.Config, path string, pal theme.Palette) error {
+	if cfg.ImageProtocol != "halfblock" {
+		cfg.ImageProtocol = "braille"
+	}
+	lines, err := imageLogo(cfg, path, pal)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	return nil
+}
 
-		calculatedWidth := 0
-		if cfg.EnableLogo {
-			for _, r := range logoLine {
-				if r == ' ' {
-					calculatedWidth += 1
-				} else if r >= 0x2800 && r <= 0x28FF {
-					calculatedWidth += 2
-				} else if r >= 0x2580 && r <= 0x259F {
-					calculatedWidth += 1
-				} else {
-					calculatedWidth += 1
-				}
-			}
-		}
+// cacheDir zwraca katalog plików podręcznych asfetch ($XDG_CACHE_HOME/asf). Przy --replay
+// wyniki nie pochodzą z tej maszyny, więc nie trafiają do pamięci podręcznej.
+func cacheDir(opts cliOptions) string {
+	if opts.replayDir != "" {
+		return ""
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "asf")
+}
 
-		spacing := 4
-		if cfg.EnableLogo {
-			fmt.Printf("%s%s%s%s%s%s\n", ColorValueLight, logoLine, ColorReset, strings.Repeat(" ", maxLogoWidth-calculatedWidth+spacing), infoLine, ColorReset)
-		} else {
-			fmt.Printf("%s%s\n", strings.Repeat(" ", maxLogoWidth+spacing), infoLine)
+// loadPalette zwraca paletę motywu z konfiguracji, a przy błędzie paletę motywu domyślnego.
+func loadPalette(cfg config.Config) theme.Palette {
+	t, err := cfg.LoadTheme()
+	if err == nil {
+		var pal theme.Palette
+		if pal, err = t.Palette(); err == nil {
+			return pal
 		}
 	}
-
-	fmt.Printf("%s\n", strings.Repeat(" ", maxLogoWidth))
+	fmt.Fprintf(os.Stderr, "Błąd wczytywania motywu %q: %v. Używam motywu domyślnego.\n", cfg.Theme, err)
+	pal, _ := theme.Default().Palette()
+	return pal
 }
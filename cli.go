@@ -0,0 +1,172 @@
+package main
+
+import (
+	"asf/config"
+	"asf/fetch"
+	"asf/logo"
+	"asf/term"
+	"asf/theme"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// version nadpisywana przy budowaniu: go build -ldflags "-X main.version=..."
+var version = "2.0"
+
+type cliOptions struct {
+	configPath string
+	logoPath   string
+	noLogo     bool
+	logoWidth  int
+	convert    string
+	only       listFlag
+	disable    listFlag
+	color      term.ColorMode
+	theme      string
+	version    bool
+	format     string
+	sysroot    string
+	recordDir  string
+	replayDir  string
+}
+
+// listFlag przyjmuje listy rozdzielone przecinkami i może być podany wielokrotnie.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+func parseArgs(args []string, output io.Writer) (cliOptions, error) {
+	var opts cliOptions
+	var jsonOutput, noColor bool
+	var colorMode string
+
+	fs := flag.NewFlagSet("asfetch", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Usage = func() {
+		fmt.Fprintf(output, "Użycie: asfetch [opcje]\n\nOpcje:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(output, "\nDostępne moduły: %s\n", strings.Join(fetch.Available(), ", "))
+		fmt.Fprintf(output, "Wbudowane motywy: %s\n", strings.Join(theme.Names(), ", "))
+		fmt.Fprintf(output, "Wbudowane logo: %s\n", strings.Join(logo.Distros(), ", "))
+	}
+
+	fs.StringVar(&opts.configPath, "config", "", "użyj podanego pliku konfiguracyjnego zamiast domyślnego")
+	fs.StringVar(&opts.logoPath, "logo", "", "użyj podanego pliku z logo, logo dystrybucji (distro:NAZWA) albo auto")
+	fs.BoolVar(&opts.noLogo, "no-logo", false, "nie wyświetlaj logo")
+	fs.IntVar(&opts.logoWidth, "logo-width", 0, "szerokość logo-obrazka w kolumnach")
+	fs.StringVar(&opts.convert, "convert", "", "zamień obrazek (PNG, JPEG, GIF) na tekst z Braille'a i wypisz go, np. do art.txt")
+	fs.Var(&opts.only, "only", "pokaż tylko podane moduły, w podanej kolejności (np. cpu,gpu,ram)")
+	fs.Var(&opts.disable, "disable", "ukryj podane moduły (np. music)")
+	fs.StringVar(&colorMode, "color", "auto", "kolory: auto (tylko na terminalu, z poszanowaniem NO_COLOR i TERM=dumb), always albo never")
+	fs.BoolVar(&noColor, "no-color", false, "nie używaj kolorów (to samo co --color=never)")
+	fs.StringVar(&opts.theme, "theme", "", "użyj podanego motywu kolorów (nazwa albo plik .json)")
+	fs.BoolVar(&opts.version, "version", false, "wypisz wersję i zakończ")
+	fs.BoolVar(&jsonOutput, "json", false, "wypisz wynik jako JSON (to samo co --format=json)")
+	fs.StringVar(&opts.format, "format", "text", "format wyjścia: text lub json")
+	fs.StringVar(&opts.sysroot, "sysroot", "", "katalog główny badanego systemu (np. zamontowany obraz dysku)")
+	fs.StringVar(&opts.recordDir, "record", "", "nagraj wyjście zewnętrznych poleceń do podanego katalogu")
+	fs.StringVar(&opts.replayDir, "replay", "", "odtwórz wyjście zewnętrznych poleceń z podanego katalogu zamiast je uruchamiać")
+
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("nieoczekiwany argument %q", fs.Arg(0))
+	}
+
+	if jsonOutput {
+		opts.format = "json"
+	}
+	var err error
+	if opts.color, err = term.ParseColorMode(colorMode); err != nil {
+		return opts, err
+	}
+	if noColor {
+		opts.color = term.ColorNever
+	}
+	if opts.format != "text" && opts.format != "json" {
+		return opts, fmt.Errorf("nieznany format wyjścia %q (dostępne: text, json)", opts.format)
+	}
+	if opts.recordDir != "" && opts.replayDir != "" {
+		return opts, fmt.Errorf("opcje --record i --replay wykluczają się")
+	}
+	if opts.logoWidth < 0 {
+		return opts, fmt.Errorf("niepoprawna szerokość logo %d", opts.logoWidth)
+	}
+	if name, ok := strings.CutPrefix(opts.logoPath, logo.DistroPrefix); ok {
+		if _, err := logo.Distro(name); err != nil {
+			return opts, err
+		}
+	}
+	for _, name := range append(slices.Clone(opts.only), opts.disable...) {
+		if !fetch.Known(name) {
+			return opts, fmt.Errorf("nieznany moduł %q (dostępne: %s)", name, strings.Join(fetch.Available(), ", "))
+		}
+	}
+	return opts, nil
+}
+
+// apply nadpisuje wczytaną konfigurację opcjami z linii poleceń, tylko na czas tego uruchomienia.
+func (o cliOptions) apply(cfg *config.Config) error {
+	if len(o.only) > 0 {
+		// Wybrane moduły zachowują nadpisania z konfiguracji, separatory i puste linie znikają.
+		only := make([]config.ModuleEntry, 0, len(o.only))
+		for _, name := range o.only {
+			entry := config.ModuleEntry{Name: name}
+			if i := slices.IndexFunc(cfg.Modules, func(e config.ModuleEntry) bool { return e.Name == name }); i >= 0 {
+				entry = cfg.Modules[i]
+			}
+			only = append(only, entry)
+		}
+		cfg.Modules = only
+	}
+	if len(o.disable) > 0 {
+		cfg.Modules = slices.DeleteFunc(slices.Clone(cfg.Modules), func(entry config.ModuleEntry) bool {
+			return slices.Contains(o.disable, entry.Name)
+		})
+	}
+
+	if o.logoPath != "" {
+		cfg.LogoPath = o.logoPath
+		if o.logoPath != logo.Auto && !strings.HasPrefix(o.logoPath, logo.DistroPrefix) {
+			abs, err := filepath.Abs(o.logoPath)
+			if err != nil {
+				return err
+			}
+			cfg.LogoPath = abs
+		}
+		cfg.EnableLogo = true
+	}
+	if o.theme != "" {
+		cfg.Theme = o.theme
+		if strings.HasSuffix(o.theme, ".json") || strings.ContainsRune(o.theme, filepath.Separator) {
+			abs, err := filepath.Abs(o.theme)
+			if err != nil {
+				return err
+			}
+			cfg.Theme = abs
+		}
+	}
+	if o.logoWidth > 0 {
+		cfg.LogoWidth = o.logoWidth
+	}
+	if o.noLogo {
+		cfg.EnableLogo = false
+	}
+	return nil
+}
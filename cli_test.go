@@ -0,0 +1,161 @@
+package main
+
+import (
+	"asf/config"
+	"asf/term"
+	"errors"
+	"flag"
+	"io"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseArgsLists(t *testing.T) {
+	opts, err := parseArgs([]string{"--only", "cpu, gpu", "--only=ram", "--disable", "music"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"cpu", "gpu", "ram"}; !slices.Equal(opts.only, want) {
+		t.Errorf("only = %q, chcę %q", opts.only, want)
+	}
+	if want := []string{"music"}; !slices.Equal(opts.disable, want) {
+		t.Errorf("disable = %q, chcę %q", opts.disable, want)
+	}
+}
+
+func TestParseArgsErrors(t *testing.T) {
+	tests := [][]string{
+		{"--only", "cpu,nieistniejacy"},
+		{"--disable", "xyz"},
+		{"--format", "yaml"},
+		{"--color", "yes"},
+		{"--logo", "distro:templeos"},
+		{"--record", "a", "--replay", "b"},
+		{"nadmiarowy"},
+		{"--nieznana-opcja"},
+	}
+	for _, args := range tests {
+		if _, err := parseArgs(args, io.Discard); err == nil {
+			t.Errorf("parseArgs(%q): oczekiwano błędu", args)
+		}
+	}
+}
+
+func TestParseArgsHelp(t *testing.T) {
+	if _, err := parseArgs([]string{"--help"}, io.Discard); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("--help: err = %v, chcę flag.ErrHelp", err)
+	}
+}
+
+func TestParseArgsJSON(t *testing.T) {
+	opts, err := parseArgs([]string{"--json"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.format != "json" {
+		t.Errorf("format = %q, chcę json", opts.format)
+	}
+}
+
+func TestParseArgsColor(t *testing.T) {
+	tests := []struct {
+		args []string
+		want term.ColorMode
+	}{
+		{nil, term.ColorAuto},
+		{[]string{"--color=always"}, term.ColorAlways},
+		{[]string{"--color", "never"}, term.ColorNever},
+		{[]string{"--color=always", "--no-color"}, term.ColorNever},
+	}
+	for _, tt := range tests {
+		opts, err := parseArgs(tt.args, io.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opts.color != tt.want {
+			t.Errorf("parseArgs(%q).color = %v, chcę %v", tt.args, opts.color, tt.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	base := config.Config{
+		Modules: []config.ModuleEntry{
+			{Name: "user"}, {Name: "os"}, {Name: config.ModuleSeparator}, {Name: "cpu"},
+			{Name: "gpu"}, {Name: "ram", Label: "Pamięć"}, {Name: "music"},
+		},
+		EnableLogo: true,
+		LogoPath:   "art.txt",
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		modules    []config.ModuleEntry
+		enableLogo bool
+	}{
+		{"bez opcji", nil, base.Modules, true},
+		{"only zmienia kolejność i zachowuje etykiety", []string{"--only", "ram,cpu"},
+			[]config.ModuleEntry{{Name: "ram", Label: "Pamięć"}, {Name: "cpu"}}, true},
+		{"disable", []string{"--disable", "music,os"},
+			[]config.ModuleEntry{{Name: "user"}, {Name: config.ModuleSeparator}, {Name: "cpu"}, {Name: "gpu"}, {Name: "ram", Label: "Pamięć"}}, true},
+		{"only i disable", []string{"--only", "cpu,gpu,music", "--disable", "music"}, config.Entries("cpu", "gpu"), true},
+		{"no-logo", []string{"--no-logo"}, base.Modules, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseArgs(tt.args, io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg := base
+			cfg.Modules = slices.Clone(base.Modules)
+			if err := opts.apply(&cfg); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(cfg.Modules, tt.modules) {
+				t.Errorf("Modules = %q, chcę %q", cfg.Modules, tt.modules)
+			}
+			if cfg.EnableLogo != tt.enableLogo {
+				t.Errorf("EnableLogo = %v, chcę %v", cfg.EnableLogo, tt.enableLogo)
+			}
+		})
+	}
+}
+
+func TestApplyLogoPath(t *testing.T) {
+	opts, err := parseArgs([]string{"--logo", "logo.txt"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Config{EnableLogo: false}
+	if err := opts.apply(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.EnableLogo {
+		t.Error("--logo powinno włączać logo")
+	}
+	if !filepath.IsAbs(cfg.LogoPath) || filepath.Base(cfg.LogoPath) != "logo.txt" {
+		t.Errorf("LogoPath = %q, chcę ścieżki bezwzględnej do logo.txt", cfg.LogoPath)
+	}
+	if cfg.LogoFile() != cfg.LogoPath {
+		t.Errorf("LogoFile() = %q, chcę %q", cfg.LogoFile(), cfg.LogoPath)
+	}
+}
+
+func TestApplyDistroLogo(t *testing.T) {
+	for _, spec := range []string{"auto", "distro:arch"} {
+		opts, err := parseArgs([]string{"--logo", spec}, io.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cfg config.Config
+		if err := opts.apply(&cfg); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.LogoPath != spec || !cfg.EnableLogo {
+			t.Errorf("--logo %s: LogoPath = %q, EnableLogo = %v", spec, cfg.LogoPath, cfg.EnableLogo)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"asf/fetch"
+	"asf/hardware"
+	"asf/theme"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -8,27 +11,144 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
+)
+
+const (
+	DefaultTimeoutMs = 2000
+	// Layout* to wartości pola layout: położenie logo względem tabeli.
+	LayoutLeft  = "left"
+	LayoutTop   = "top"
+	LayoutRight = "right"
+	LayoutNone  = "none"
+	// Align* to wartości pola align: wyrównanie w pionie niższej z kolumn logo i tabeli.
+	AlignTop    = "top"
+	AlignMiddle = "middle"
+	AlignBottom = "bottom"
+	// Overflow* to wartości pola overflow: co zrobić z wartością szerszą niż terminal.
+	OverflowTruncate = "truncate"
+	OverflowWrap     = "wrap"
+	// DefaultLogoWidth to szerokość logo-obrazka w kolumnach, gdy logo_width nie jest ustawione.
+	DefaultLogoWidth = 30
 )
 
 type Config struct {
-	EnableUserHost  bool   `json:"enable_user_host"`
-	EnableOSInfo    bool   `json:"enable_os_info"`
-	EnableKernel    bool   `json:"enable_kernel"`
-	EnablePackages  bool   `json:"enable_packages"`
-	EnableDEWM      bool   `json:"enable_de_wm"`
-	EnableCPU       bool   `json:"enable_cpu"`
-	EnableGPU       bool   `json:"enable_gpu"`
-	EnableRAM       bool   `json:"enable_ram"`
-	EnableSwap      bool   `json:"enable_swap"`
-	EnableMusic     bool   `json:"enable_music"`
-	EnableUptime    bool   `json:"enable_uptime"`
-	EnableGTKTheme  bool   `json:"enable_gtk_theme"`
-	EnableIconTheme bool   `json:"enable_icon_theme"`
-	EnableFont      bool   `json:"enable_font"`
-	EnableShell     bool   `json:"enable_shell"`
-	EnableBattery   bool   `json:"enable_battery"`
-	EnableLogo      bool   `json:"enable_logo"`
-	LogoPath        string `json:"logo_path"`
+	Modules          []ModuleEntry  `json:"modules"`
+	TimeoutMs        int            `json:"timeout_ms"`
+	ModuleTimeoutsMs map[string]int `json:"module_timeouts_ms,omitempty"`
+	EnableLogo       bool           `json:"enable_logo"`
+	LogoPath         string         `json:"logo_path"`
+	// LogoColors nadpisuje kolory znaczników logo, np. {"c1": "red", "c2": "#ff8800"}.
+	LogoColors map[string]string `json:"logo_colors,omitempty"`
+	// LogoWidth to szerokość w kolumnach, do której skalowane jest logo-obrazek (.png, .jpg, .gif).
+	LogoWidth int `json:"logo_width,omitempty"`
+	// ImageProtocol wybiera sposób rysowania logo-obrazka: auto, kitty, sixel, iterm2
+	// albo zamianę na tekst: halfblock, braille.
+	ImageProtocol string `json:"image_protocol,omitempty"`
+	// Layout to Layout* (domyślnie LayoutLeft).
+	Layout string `json:"layout,omitempty"`
+	// Align to Align* (domyślnie AlignTop).
+	Align string `json:"align,omitempty"`
+	// Box to styl ramki wokół tabeli: single, rounded, double albo heavy; pusty oznacza brak ramki.
+	Box string `json:"box,omitempty"`
+	// Gap to odstęp w kolumnach między logo a tabelą (domyślnie 4).
+	Gap *int `json:"gap,omitempty"`
+	// PaddingTop i PaddingBottom to puste linie nad i pod wyjściem (domyślnie 1), PaddingLeft
+	// to wcięcie całości z lewej (domyślnie 0).
+	PaddingTop    *int `json:"padding_top,omitempty"`
+	PaddingBottom *int `json:"padding_bottom,omitempty"`
+	PaddingLeft   *int `json:"padding_left,omitempty"`
+	// Overflow to OverflowTruncate (domyślnie) albo OverflowWrap.
+	Overflow string `json:"overflow,omitempty"`
+	// Disk to ustawienia modułu disk: wybrane punkty montowania i pokazywanie typu systemu plików.
+	Disk hardware.DiskOptions `json:"disk"`
+	// Network to ustawienia modułu network: ukrywane interfejsy i pokazywane rodziny adresów.
+	Network hardware.NetworkOptions `json:"network"`
+	// Theme to nazwa motywu: zdefiniowanego w Themes, pliku themes/<nazwa>.json w katalogu
+	// konfiguracji albo motywu wbudowanego. Może też być ścieżką do pliku .json.
+	Theme  string                 `json:"theme,omitempty"`
+	Themes map[string]theme.Theme `json:"themes,omitempty"`
+
+	dir string
+}
+
+// Timeout zwraca łączny limit czasu na pobranie wszystkich modułów.
+func (c Config) Timeout() time.Duration {
+	if c.TimeoutMs <= 0 {
+		return DefaultTimeoutMs * time.Millisecond
+	}
+	return time.Duration(c.TimeoutMs) * time.Millisecond
+}
+
+// ModuleTimeouts zwraca limity czasu dla pojedynczych modułów; moduły bez wpisu podlegają tylko limitowi łącznemu.
+func (c Config) ModuleTimeouts() map[string]time.Duration {
+	timeouts := make(map[string]time.Duration, len(c.ModuleTimeoutsMs))
+	for name, ms := range c.ModuleTimeoutsMs {
+		if ms > 0 {
+			timeouts[name] = time.Duration(ms) * time.Millisecond
+		}
+	}
+	return timeouts
+}
+
+// ModuleOptions zwraca ustawienia modułów w postaci oczekiwanej przez fetch.Options.
+func (c Config) ModuleOptions() map[string]any {
+	return map[string]any{
+		"disk":    c.Disk,
+		"network": c.Network,
+	}
+}
+
+// legacyConfig odpowiada starszemu formatowi pliku, w którym każdy moduł miał własne pole enable_*.
+type legacyConfig struct {
+	EnableUserHost  bool `json:"enable_user_host"`
+	EnableOSInfo    bool `json:"enable_os_info"`
+	EnableKernel    bool `json:"enable_kernel"`
+	EnablePackages  bool `json:"enable_packages"`
+	EnableDEWM      bool `json:"enable_de_wm"`
+	EnableCPU       bool `json:"enable_cpu"`
+	EnableGPU       bool `json:"enable_gpu"`
+	EnableRAM       bool `json:"enable_ram"`
+	EnableSwap      bool `json:"enable_swap"`
+	EnableMusic     bool `json:"enable_music"`
+	EnableUptime    bool `json:"enable_uptime"`
+	EnableGTKTheme  bool `json:"enable_gtk_theme"`
+	EnableIconTheme bool `json:"enable_icon_theme"`
+	EnableFont      bool `json:"enable_font"`
+	EnableShell     bool `json:"enable_shell"`
+	EnableBattery   bool `json:"enable_battery"`
+}
+
+func (l legacyConfig) modules() []ModuleEntry {
+	flags := []struct {
+		enabled bool
+		names   []string
+	}{
+		{l.EnableUserHost, []string{"user"}},
+		{l.EnableOSInfo, []string{"os"}},
+		{l.EnableKernel, []string{"kernel"}},
+		{l.EnablePackages, []string{"packages"}},
+		{l.EnableDEWM, []string{"de", "wm"}},
+		{l.EnableGTKTheme, []string{"gtk"}},
+		{l.EnableIconTheme, []string{"icons"}},
+		{l.EnableFont, []string{"font"}},
+		{l.EnableShell, []string{"shell"}},
+		{l.EnableUptime, []string{"uptime"}},
+		{l.EnableBattery, []string{"battery"}},
+		{l.EnableCPU, []string{"cpu"}},
+		{l.EnableGPU, []string{"gpu"}},
+		{l.EnableRAM, []string{"ram"}},
+		{l.EnableSwap, []string{"swap"}},
+		{l.EnableMusic, []string{"music"}},
+	}
+
+	mods := []ModuleEntry{}
+	for _, f := range flags {
+		if f.enabled {
+			mods = append(mods, Entries(f.names...)...)
+		}
+	}
+	return mods
 }
 
 var (
@@ -40,25 +160,28 @@ var (
 
 func GetDefaultConfig() Config {
 	return Config{
-		EnableUserHost:  true,
-		EnableOSInfo:    true,
-		EnableKernel:    true,
-		EnablePackages:  true,
-		EnableDEWM:      true,
-		EnableCPU:       true,
-		EnableGPU:       true,
-		EnableRAM:       true,
-		EnableSwap:      true,
-		EnableMusic:     true,
-		EnableUptime:    true,
-		EnableGTKTheme:  false,
-		EnableIconTheme: false,
-		EnableFont:      false,
-		EnableShell:     false,
-		EnableBattery:   false,
-		EnableLogo:      true,
-		LogoPath:        "art.txt",
+		Modules:    Entries(fetch.DefaultModules()...),
+		TimeoutMs:  DefaultTimeoutMs,
+		EnableLogo: true,
+		LogoPath:   "art.txt",
+		Theme:      theme.DefaultName,
+	}
+}
+
+func parseConfig(data []byte) (Config, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.Modules == nil {
+		var legacy legacyConfig
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return Config{}, err
+		}
+		cfg.Modules = legacy.modules()
 	}
+	return cfg, nil
 }
 
 func GetUserConfigDir() string {
@@ -156,32 +279,75 @@ func LoadConfig() Config {
 			return
 		}
 
-		data, err := ioutil.ReadFile(configFilePath)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Błąd odczytu pliku konfiguracyjnego z %s: %v. Używam domyślnej konfiguracji.\n", configFilePath, err)
-			appConfig = GetDefaultConfig()
-			return
-		}
-
-		err = json.Unmarshal(data, &appConfig)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Błąd parsowania konfiguracji z %s: %v. Używam domyślnej konfiguracji.\n", configFilePath, err)
-			appConfig = GetDefaultConfig()
-		}
+		appConfig = LoadConfigFrom(configFilePath)
 	})
 	return appConfig
 }
 
-func LoadLogoFromFile(path string) ([]string, error) {
+// LoadConfigFrom wczytuje konfigurację ze wskazanego pliku (np. z --config), bez tworzenia
+// domyślnych plików. Przy błędzie zwraca konfigurację domyślną.
+func LoadConfigFrom(configFilePath string) Config {
+	cfg := GetDefaultConfig()
 
-	if !filepath.IsAbs(path) {
-		path = filepath.Join(GetUserConfigDir(), path)
+	data, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Błąd odczytu pliku konfiguracyjnego z %s: %v. Używam domyślnej konfiguracji.\n", configFilePath, err)
+		return cfg
 	}
 
-	data, err := ioutil.ReadFile(path)
+	parsed, err := parseConfig(data)
 	if err != nil {
-		return nil, fmt.Errorf("nie udało się wczytać logo z pliku %s: %w", path, err)
+		fmt.Fprintf(os.Stderr, "Błąd parsowania konfiguracji z %s: %v. Używam domyślnej konfiguracji.\n", configFilePath, err)
+		return cfg
+	}
+	parsed.dir = filepath.Dir(configFilePath)
+	return parsed
+}
+
+// LogoColumns zwraca szerokość logo-obrazka w kolumnach.
+func (c Config) LogoColumns() int {
+	if c.LogoWidth <= 0 {
+		return DefaultLogoWidth
+	}
+	return c.LogoWidth
+}
+
+// LogoFile zwraca ścieżkę do pliku logo; ścieżki względne liczone są od katalogu pliku konfiguracyjnego.
+func (c Config) LogoFile() string {
+	return c.path(c.LogoPath)
+}
+
+// LoadTheme zwraca motyw wskazany w konfiguracji.
+func (c Config) LoadTheme() (theme.Theme, error) {
+	name := c.Theme
+	if name == "" {
+		return theme.Default(), nil
+	}
+	if t, ok := c.Themes[name]; ok {
+		return t, nil
+	}
+	if strings.HasSuffix(name, ".json") || strings.ContainsRune(name, filepath.Separator) {
+		return theme.LoadFile(c.path(name))
+	}
+	if file := c.path(filepath.Join("themes", name+".json")); fileExists(file) {
+		return theme.LoadFile(file)
 	}
-	lines := strings.Split(string(data), "\n")
-	return lines, nil
+	return theme.Bundled(name)
+}
+
+// path zwraca ścieżkę względną liczoną od katalogu pliku konfiguracyjnego.
+func (c Config) path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	dir := c.dir
+	if dir == "" {
+		dir = GetUserConfigDir()
+	}
+	return filepath.Join(dir, name)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
 }
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"asf/theme"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseConfigLegacyFlags(t *testing.T) {
+	cfg, err := parseConfig([]byte(`{
+  "enable_user_host": true,
+  "enable_os_info": true,
+  "enable_de_wm": true,
+  "enable_cpu": true,
+  "enable_music": false,
+  "enable_logo": true,
+  "logo_path": "art.txt"
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Entries("user", "os", "de", "wm", "cpu")
+	if !reflect.DeepEqual(cfg.Modules, want) {
+		t.Errorf("Modules = %v, want %v", cfg.Modules, want)
+	}
+	if !cfg.EnableLogo || cfg.LogoPath != "art.txt" {
+		t.Errorf("logo settings lost: %+v", cfg)
+	}
+	if cfg.Timeout() != DefaultTimeoutMs*time.Millisecond {
+		t.Errorf("Timeout() = %v", cfg.Timeout())
+	}
+}
+
+func TestParseConfigModules(t *testing.T) {
+	cfg, err := parseConfig([]byte(`{"modules": ["cpu", "gpu"], "enable_cpu": false, "module_timeouts_ms": {"gpu": 500}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(cfg.Modules, Entries("cpu", "gpu")) {
+		t.Errorf("Modules = %v", cfg.Modules)
+	}
+	if got := cfg.ModuleTimeouts()["gpu"]; got != 500*time.Millisecond {
+		t.Errorf("gpu timeout = %v", got)
+	}
+}
+
+func TestParseConfigModuleEntries(t *testing.T) {
+	cfg, err := parseConfig([]byte(`{"modules": [
+  "user",
+  "separator",
+  {"name": "music", "label": "Music", "icon": "♪", "color": "magenta"},
+  "break",
+  {"name": "cpu"},
+  "separator",
+  "music"
+]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ModuleEntry{
+		{Name: "user"},
+		{Name: ModuleSeparator},
+		{Name: "music", Label: "Music", Icon: "♪", Color: "magenta"},
+		{Name: ModuleBreak},
+		{Name: "cpu"},
+		{Name: ModuleSeparator},
+		{Name: "music"},
+	}
+	if !reflect.DeepEqual(cfg.Modules, want) {
+		t.Errorf("Modules = %+v, want %+v", cfg.Modules, want)
+	}
+	if got, want := cfg.ModuleNames(), []string{"user", "music", "cpu"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ModuleNames() = %v, want %v", got, want)
+	}
+}
+
+func TestParseConfigModuleEntryErrors(t *testing.T) {
+	for _, data := range []string{
+		`{"modules": [{"label": "bez nazwy"}]}`,
+		`{"modules": [42]}`,
+	} {
+		if _, err := parseConfig([]byte(data)); err == nil {
+			t.Errorf("parseConfig(%s): oczekiwano błędu", data)
+		}
+	}
+}
+
+func TestModuleEntryMarshal(t *testing.T) {
+	data, err := json.Marshal([]ModuleEntry{{Name: "cpu"}, {Name: "music", Label: "Music"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `["cpu",{"name":"music","label":"Music"}]`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "themes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "themes", "plik.json"), []byte(`{"label": "green"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		theme string
+		label string
+	}{
+		{"domyślny", "", theme.Default().Label},
+		{"z konfiguracji", "moj", "red"},
+		{"z katalogu themes", "plik", "green"},
+		{"ścieżka względna", "themes/plik.json", "green"},
+		{"wbudowany", "nord", "#88c0d0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				Theme:  tt.theme,
+				Themes: map[string]theme.Theme{"moj": {Label: "red"}},
+				dir:    dir,
+			}
+			th, err := cfg.LoadTheme()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if th.Label != tt.label {
+				t.Errorf("Label = %q, want %q", th.Label, tt.label)
+			}
+		})
+	}
+
+	if _, err := (Config{Theme: "nieistniejacy", dir: dir}).LoadTheme(); err == nil {
+		t.Error("oczekiwano błędu dla nieznanego motywu")
+	}
+}
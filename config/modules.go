@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Wpisy układu, które nie są modułami: linia oddzielająca i pusta linia. Można je powtarzać.
+const (
+	ModuleSeparator = "separator"
+	ModuleBreak     = "break"
+)
+
+// ModuleEntry to pojedynczy wpis listy "modules". W pliku może być samą nazwą ("cpu")
+// albo obiektem z nadpisaną etykietą, ikoną i kolorem etykiety:
+//
+//	{"name": "music", "label": "Music", "icon": "♪", "color": "magenta"}
+type ModuleEntry struct {
+	Name  string `json:"name"`
+	Label string `json:"label,omitempty"`
+	Icon  string `json:"icon,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+// Entries zamienia listę nazw na wpisy bez nadpisań.
+func Entries(names ...string) []ModuleEntry {
+	entries := make([]ModuleEntry, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, ModuleEntry{Name: name})
+	}
+	return entries
+}
+
+// IsLayout mówi, czy wpis jest elementem układu (separator, pusta linia), a nie modułem.
+func (e ModuleEntry) IsLayout() bool {
+	return e.Name == ModuleSeparator || e.Name == ModuleBreak
+}
+
+func (e *ModuleEntry) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*e = ModuleEntry{Name: name}
+		return nil
+	}
+
+	type plain ModuleEntry
+	var entry plain
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return fmt.Errorf("wpis modułu musi być nazwą albo obiektem z polem name: %w", err)
+	}
+	if entry.Name == "" {
+		return fmt.Errorf("wpis modułu bez nazwy: %s", data)
+	}
+	*e = ModuleEntry(entry)
+	return nil
+}
+
+// MarshalJSON zapisuje wpis bez nadpisań jako samą nazwę, żeby domyślny plik był czytelny.
+func (e ModuleEntry) MarshalJSON() ([]byte, error) {
+	if e.Label == "" && e.Icon == "" && e.Color == "" {
+		return json.Marshal(e.Name)
+	}
+	type plain ModuleEntry
+	return json.Marshal(plain(e))
+}
+
+// ModuleNames zwraca nazwy modułów do pobrania, w kolejności pierwszego wystąpienia,
+// bez elementów układu i powtórzeń.
+func (c Config) ModuleNames() []string {
+	seen := make(map[string]bool, len(c.Modules))
+	names := make([]string, 0, len(c.Modules))
+	for _, entry := range c.Modules {
+		if entry.IsLayout() || seen[entry.Name] {
+			continue
+		}
+		seen[entry.Name] = true
+		names = append(names, entry.Name)
+	}
+	return names
+}
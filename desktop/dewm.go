@@ -1,20 +1,22 @@
 package desktop
 
 import (
-	"os"
-	"os/exec"
+	"asf/system"
+	"context"
 	"regexp"
 	"strings"
 )
 
-func GetDEWM() (string, string) {
-	if os.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != "" {
+// GetDEWM zwraca środowisko graficzne i menedżer okien sesji. Zmienne sesji pochodzą z sys.Getenv,
+// więc dla obcego korzenia, którego środowisko jest nieznane, zostaje tylko wykrywanie procesów.
+func GetDEWM(ctx context.Context, sys *system.System) (string, string) {
+	if sys.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != "" {
 		return "", "Hyprland"
 	}
 
-	de := os.Getenv("XDG_CURRENT_DESKTOP")
+	de := sys.Getenv("XDG_CURRENT_DESKTOP")
 	if de == "" {
-		de = os.Getenv("DESKTOP_SESSION")
+		de = sys.Getenv("DESKTOP_SESSION")
 	}
 	if de != "" {
 		index := strings.Index(de, ":")
@@ -23,7 +25,7 @@ func GetDEWM() (string, string) {
 		}
 	}
 
-	wm := DetectWM()
+	wm := DetectWM(ctx, sys)
 
 	if strings.Contains(strings.ToLower(de), strings.ToLower(wm)) {
 		return de, ""
@@ -32,7 +34,7 @@ func GetDEWM() (string, string) {
 	return de, wm
 }
 
-func DetectWM() string {
+func DetectWM(ctx context.Context, sys *system.System) string {
 	wmProcesses := map[string]string{
 		"hyprland": "Hyprland",
 		"sway":     "Sway",
@@ -52,15 +54,15 @@ func DetectWM() string {
 	}
 
 	for proc, name := range wmProcesses {
-		if out, err := exec.Command("pgrep", "-x", proc).Output(); err == nil && len(out) > 0 {
+		if out, err := sys.Output(ctx, "pgrep", "-x", proc); err == nil && len(out) > 0 {
 			return name
 		}
 	}
 
-	if os.Getenv("DISPLAY") != "" {
-		if out, err := exec.Command("xprop", "-root", "_NET_SUPPORTING_WM_CHECK").Output(); err == nil {
+	if sys.Getenv("DISPLAY") != "" {
+		if out, err := sys.Output(ctx, "xprop", "-root", "_NET_SUPPORTING_WM_CHECK"); err == nil {
 			if id := strings.TrimPrefix(strings.TrimSpace(string(out)), "_NET_SUPPORTING_WM_CHECK(WINDOW): window id # "); id != "" {
-				if out, err := exec.Command("xprop", "-id", id, "WM_NAME").Output(); err == nil {
+				if out, err := sys.Output(ctx, "xprop", "-id", id, "WM_NAME"); err == nil {
 					if match := regexp.MustCompile(`WM_NAME\(\w+\) = (.+)`).FindStringSubmatch(string(out)); len(match) > 1 {
 						return strings.Trim(match[1], "\"")
 					}
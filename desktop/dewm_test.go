@@ -0,0 +1,92 @@
+package desktop
+
+import (
+	"asf/system"
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+// replay zwraca system odtwarzający polecenia nagrane przez system.Recorder w danej sesji
+// (testdata/commands/<sesja>). Polecenia bez nagrania zachowują się jak nieobecne w PATH,
+// tak samo jak pgrep, który nie znalazł procesu.
+func replay(session string, vars map[string]string) *system.System {
+	return system.FromFS(fstest.MapFS{}).
+		WithRunner(system.Replayer{Dir: filepath.Join("testdata", "commands", session)}).
+		WithEnv(env(vars))
+}
+
+func TestDetectWM(t *testing.T) {
+	tests := []struct {
+		session string
+		env     map[string]string
+		want    string
+	}{
+		{session: "sway", want: "Sway"},
+		{session: "xfce-x11", env: map[string]string{"DISPLAY": ":0"}, want: "Xfwm4"},
+		// Bez DISPLAY xprop nie jest w ogóle pytany.
+		{session: "xfce-x11", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.session, func(t *testing.T) {
+			if got := DetectWM(context.Background(), replay(tt.session, tt.env)); got != tt.want {
+				t.Errorf("DetectWM() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDEWM(t *testing.T) {
+	tests := []struct {
+		name    string
+		session string
+		env     map[string]string
+		de, wm  string
+	}{
+		{
+			name:    "gnome without separate wm",
+			session: "gnome",
+			env:     map[string]string{"XDG_CURRENT_DESKTOP": "ubuntu:GNOME"},
+			de:      "GNOME",
+		},
+		{
+			name:    "hyprland signature",
+			session: "hyprland",
+			env:     map[string]string{"HYPRLAND_INSTANCE_SIGNATURE": "abc", "XDG_CURRENT_DESKTOP": "Hyprland"},
+			wm:      "Hyprland",
+		},
+		{
+			name:    "xfce with i3",
+			session: "xfce-i3",
+			env:     map[string]string{"XDG_CURRENT_DESKTOP": "XFCE"},
+			de:      "XFCE",
+			wm:      "i3",
+		},
+		{
+			name:    "sway session",
+			session: "sway",
+			env:     map[string]string{"XDG_CURRENT_DESKTOP": "sway"},
+			de:      "sway",
+		},
+		{
+			name:    "sysroot without session environment",
+			session: "sway",
+			wm:      "Sway",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			de, wm := GetDEWM(context.Background(), replay(tt.session, tt.env))
+			if de != tt.de || wm != tt.wm {
+				t.Errorf("GetDEWM() = (%q, %q), want (%q, %q)", de, wm, tt.de, tt.wm)
+			}
+		})
+	}
+}
@@ -1,18 +1,10 @@
 package desktop
 
 import (
-	"os/exec"
-	"runtime"
-	"strings"
+	"asf/system"
+	"context"
 )
 
-func GetFont() string {
-	if runtime.GOOS == "linux" {
-		if _, err := exec.LookPath("gsettings"); err == nil {
-			if out, err := exec.Command("gsettings", "get", "org.gnome.desktop.interface", "font-name").Output(); err == nil {
-				return strings.Trim(strings.TrimSpace(string(out)), "'")
-			}
-		}
-	}
-	return "unknown"
+func GetFont(ctx context.Context, sys *system.System) (string, error) {
+	return gsettingsInterface(ctx, sys, "font-name")
 }
@@ -1,29 +1,32 @@
 package desktop
 
 import (
-	"os/exec"
+	"asf/modules"
+	"asf/system"
+	"context"
 	"runtime"
 	"strings"
 )
 
-func GetGTKTheme() string {
-	if runtime.GOOS == "linux" {
-		if _, err := exec.LookPath("gsettings"); err == nil {
-			if out, err := exec.Command("gsettings", "get", "org.gnome.desktop.interface", "gtk-theme").Output(); err == nil {
-				return strings.Trim(strings.TrimSpace(string(out)), "'")
-			}
-		}
+func gsettingsInterface(ctx context.Context, sys *system.System, key string) (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", modules.ErrUnavailable
 	}
-	return "unknown"
+	out, err := sys.Output(ctx, "gsettings", "get", "org.gnome.desktop.interface", key)
+	if err != nil {
+		return "", modules.ErrUnavailable
+	}
+	value := strings.Trim(strings.TrimSpace(string(out)), "'")
+	if value == "" {
+		return "", modules.ErrUnavailable
+	}
+	return value, nil
 }
 
-func GetIconTheme() string {
-	if runtime.GOOS == "linux" {
-		if _, err := exec.LookPath("gsettings"); err == nil {
-			if out, err := exec.Command("gsettings", "get", "org.gnome.desktop.interface", "icon-theme").Output(); err == nil {
-				return strings.Trim(strings.TrimSpace(string(out)), "'")
-			}
-		}
-	}
-	return "unknown"
+func GetGTKTheme(ctx context.Context, sys *system.System) (string, error) {
+	return gsettingsInterface(ctx, sys, "gtk-theme")
+}
+
+func GetIconTheme(ctx context.Context, sys *system.System) (string, error) {
+	return gsettingsInterface(ctx, sys, "icon-theme")
 }
@@ -0,0 +1,45 @@
+package desktop
+
+import (
+	"asf/modules"
+	"asf/system"
+	"context"
+)
+
+type deWM struct {
+	de string
+	wm string
+}
+
+func cachedDEWM(ctx context.Context, sys *system.System) (deWM, error) {
+	return modules.Cached(ctx, "desktop.dewm", func(ctx context.Context) (deWM, error) {
+		de, wm := GetDEWM(ctx, sys)
+		return deWM{de, wm}, ctx.Err()
+	})
+}
+
+func init() {
+	modules.Register(modules.Func("de", "DE", modules.DataContext(func(ctx context.Context, sys *system.System) (string, error) {
+		v, err := cachedDEWM(ctx, sys)
+		if err != nil {
+			return "", err
+		}
+		if v.de == "" {
+			return "", modules.ErrUnavailable
+		}
+		return v.de, nil
+	})))
+	modules.Register(modules.Func("wm", "WM", modules.DataContext(func(ctx context.Context, sys *system.System) (string, error) {
+		v, err := cachedDEWM(ctx, sys)
+		if err != nil {
+			return "", err
+		}
+		if v.wm == "" {
+			return "", modules.ErrUnavailable
+		}
+		return v.wm, nil
+	})))
+	modules.Register(modules.Func("gtk", "GTK", modules.DataContext(GetGTKTheme)))
+	modules.Register(modules.Func("icons", "Icons", modules.DataContext(GetIconTheme)))
+	modules.Register(modules.Func("font", "Font", modules.DataContext(GetFont)))
+}
@@ -0,0 +1,406 @@
+package dodatki
+
+import (
+	"asf/modules"
+	"asf/system"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestGetUptime(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/uptime": {Data: []byte("266523.41 1043322.73\n")},
+	}
+
+	got, err := GetUptime(system.FromFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 266523 * time.Second; got != want {
+		t.Errorf("GetUptime() = %v, want %v", got, want)
+	}
+}
+
+func TestGetUptimeMalformed(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/uptime": {Data: []byte("\n")},
+	}
+
+	if _, err := GetUptime(system.FromFS(fsys)); err == nil {
+		t.Error("expected error for empty /proc/uptime")
+	}
+}
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestGetUserAndHost(t *testing.T) {
+	fsys := fstest.MapFS{
+		"etc/hostname": {Data: []byte("gozdnica\n")},
+	}
+
+	tests := []struct {
+		name string
+		sys  *system.System
+		want UserHost
+	}{
+		{"session", system.FromFS(fsys).WithEnv(env(map[string]string{"USER": "lis"})), UserHost{User: "lis", Host: "gozdnica"}},
+		{"sysroot without environment", system.FromFS(fsys), UserHost{Host: "gozdnica"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetUserAndHost(tt.sys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("GetUserAndHost() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCurrentMusic(t *testing.T) {
+	tests := []struct {
+		name   string
+		runner system.StaticRunner
+		want   Track
+		err    error
+	}{
+		{
+			name: "spotify",
+			runner: system.StaticRunner{
+				"playerctl -p spotify metadata --format " + playerctlFormat: "Kult\tArahja\n",
+			},
+			want: Track{Artist: "Kult", Title: "Arahja"},
+		},
+		{
+			name: "other player without artist",
+			runner: system.StaticRunner{
+				"playerctl metadata --format " + playerctlFormat: "\tPodcast odc. 12\n",
+			},
+			want: Track{Title: "Podcast odc. 12"},
+		},
+		{
+			name:   "nothing playing",
+			runner: system.StaticRunner{},
+			err:    modules.ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := system.FromFS(fstest.MapFS{}).WithRunner(tt.runner)
+			got, err := GetCurrentMusic(context.Background(), sys)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("GetCurrentMusic() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// procTree buduje /proc z łańcuchem procesów; pierwszy wpis to asfetch (self), kolejne to jego przodkowie.
+func procTree(fsys fstest.MapFS, comms ...string) fstest.MapFS {
+	for i, comm := range comms {
+		pid := 1000 - i
+		stat := fmt.Sprintf("%d (%s) S %d 1 1 34816 0\n", pid, comm, pid-1)
+		if i == len(comms)-1 {
+			stat = fmt.Sprintf("%d (%s) S 1 1 1 0 0\n", pid, comm)
+		}
+		if i == 0 {
+			fsys["proc/self/stat"] = &fstest.MapFile{Data: []byte(stat)}
+		}
+		fsys[fmt.Sprintf("proc/%d/stat", pid)] = &fstest.MapFile{Data: []byte(stat)}
+		fsys[fmt.Sprintf("proc/%d/comm", pid)] = &fstest.MapFile{Data: []byte(comm + "\n")}
+	}
+	return fsys
+}
+
+func TestGetTerminal(t *testing.T) {
+	tests := []struct {
+		name  string
+		chain []string
+		env   map[string]string
+		want  Terminal
+		err   error
+	}{
+		{
+			name:  "kitty",
+			chain: []string{"asfetch", "zsh", "kitty", "systemd"},
+			want:  Terminal{Name: "kitty", Process: "kitty"},
+		},
+		{
+			name:  "gnome terminal with truncated comm",
+			chain: []string{"asfetch", "sudo", "bash", "gnome-terminal-", "systemd"},
+			want:  Terminal{Name: "GNOME Terminal", Process: "gnome-terminal-"},
+		},
+		{
+			name:  "inside tmux",
+			chain: []string{"asfetch", "fish", "tmux: server"},
+			want:  Terminal{Name: "tmux", Process: "tmux: server"},
+		},
+		{
+			name:  "TERM_PROGRAM fallback",
+			chain: []string{"asfetch", "bash", "cron"},
+			env:   map[string]string{"TERM_PROGRAM": "vscode"},
+			want:  Terminal{Name: "vscode"},
+		},
+		{
+			name:  "unknown",
+			chain: []string{"asfetch", "bash", "cron"},
+			err:   modules.ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetTerminal(system.FromFS(procTree(fstest.MapFS{}, tt.chain...)).WithEnv(env(tt.env)))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("GetTerminal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadProcessCommWithParens(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/self/stat": {Data: []byte("4242 (Web Content (x)) S 4100 4100 4100 0 -1\n")},
+	}
+	got, err := readProcess(system.FromFS(fsys), "self")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (process{pid: 4242, ppid: 4100, comm: "Web Content (x)"}); got != want {
+		t.Errorf("readProcess() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetTerminalFont(t *testing.T) {
+	fsys := fstest.MapFS{
+		"home/lis/.config/kitty/kitty.conf": {Data: []byte("# font\nfont_family\tJetBrains Mono\nbold_font auto\nfont_size 11.5\n")},
+		"home/lis/.config/alacritty/alacritty.toml": {Data: []byte(`[window]
+opacity = 0.9
+
+[font]
+size = 12.0
+
+[font.normal]
+family = "Fira Code"
+style = "Regular"
+`)},
+		"home/lis/.config/foot/foot.ini":       {Data: []byte("[main]\nfont=Iosevka Term:size=10, Noto Color Emoji:size=10\n")},
+		"home/lis/.config/ghostty/config":      {Data: []byte("font-family = Berkeley Mono\ntheme = nord\n")},
+		"home/lis/.config/wezterm/wezterm.lua": {Data: []byte("local config = wezterm.config_builder()\nconfig.font = wezterm.font('Hack')\nconfig.font_size = 13\nreturn config\n")},
+	}
+	sys := system.FromFS(fsys).WithEnv(env(map[string]string{"HOME": "/home/lis"}))
+
+	tests := []struct {
+		terminal string
+		want     TerminalFont
+		err      error
+	}{
+		{"kitty", TerminalFont{Family: "JetBrains Mono", Size: 11.5}, nil},
+		{"Alacritty", TerminalFont{Family: "Fira Code", Size: 12}, nil},
+		{"foot", TerminalFont{Family: "Iosevka Term", Size: 10}, nil},
+		{"Ghostty", TerminalFont{Family: "Berkeley Mono"}, nil},
+		{"WezTerm", TerminalFont{Family: "Hack", Size: 13}, nil},
+		{"Konsole", TerminalFont{}, modules.ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.terminal, func(t *testing.T) {
+			got, err := GetTerminalFont(sys, Terminal{Name: tt.terminal})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("GetTerminalFont() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// linkFS dodaje do fstest.MapFS dowiązania symboliczne, takie jak /proc/<pid>/exe.
+type linkFS struct {
+	fstest.MapFS
+	links map[string]string
+}
+
+func (l linkFS) ReadLink(name string) (string, error) {
+	if target, ok := l.links[name]; ok {
+		return target, nil
+	}
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+}
+
+func TestGetShell(t *testing.T) {
+	exe := &fstest.MapFile{Data: make([]byte, 900), ModTime: time.Unix(1700000000, 0)}
+	bashVersion := "GNU bash, version 5.2.26(1)-release (x86_64-pc-linux-gnu)\n"
+
+	tests := []struct {
+		name   string
+		fsys   fstest.MapFS
+		links  map[string]string
+		runner system.StaticRunner
+		want   Shell
+	}{
+		{
+			name:   "zsh started from login bash",
+			fsys:   procTree(fstest.MapFS{"proc/999/exe": exe}, "asfetch", "zsh", "bash", "kitty"),
+			links:  map[string]string{"proc/999/exe": "/usr/bin/zsh"},
+			runner: system.StaticRunner{"/usr/bin/zsh --version": "zsh 5.9 (x86_64-pc-linux-gnu)\n"},
+			want:   Shell{Name: "zsh", Version: "5.9"},
+		},
+		{
+			name:   "fish replaced by an update",
+			fsys:   procTree(fstest.MapFS{}, "asfetch", "fish", "foot"),
+			links:  map[string]string{"proc/999/exe": "/usr/bin/fish (deleted)"},
+			runner: system.StaticRunner{"/usr/bin/fish --version": "fish, version 3.7.1\n"},
+			want:   Shell{Name: "fish", Version: "3.7.1"},
+		},
+		{
+			name:   "dash version from package manager",
+			fsys:   procTree(fstest.MapFS{}, "asfetch", "dash", "sshd"),
+			links:  map[string]string{"proc/999/exe": "/usr/bin/dash"},
+			runner: system.StaticRunner{"dpkg-query -W -f=${Version} dash": "0.5.12-6"},
+			want:   Shell{Name: "dash", Version: "0.5.12"},
+		},
+		{
+			name:   "sh is bash on Fedora",
+			fsys:   procTree(fstest.MapFS{}, "asfetch", "sh", "sshd"),
+			links:  map[string]string{"proc/999/exe": "/usr/bin/bash"},
+			runner: system.StaticRunner{"/usr/bin/bash --version": bashVersion},
+			want:   Shell{Name: "bash", Version: "5.2.26(1)-release"},
+		},
+		{
+			name:   "sh is busybox on Alpine",
+			fsys:   procTree(fstest.MapFS{}, "asfetch", "sh", "sshd"),
+			links:  map[string]string{"proc/999/exe": "/bin/busybox"},
+			runner: system.StaticRunner{"/bin/busybox --help": "BusyBox v1.36.1 (2024-06-10 07:11:47 UTC) multi-call binary.\n"},
+			want:   Shell{Name: "busybox", Version: "1.36.1"},
+		},
+		{
+			name:   "unreadable exe link runs the process image",
+			fsys:   procTree(fstest.MapFS{}, "asfetch", "zsh", "kitty"),
+			runner: system.StaticRunner{"/proc/999/exe --version": "zsh 5.9 (x86_64-pc-linux-gnu)\n"},
+			want:   Shell{Name: "zsh", Version: "5.9"},
+		},
+		{
+			name:   "no process tree falls back to $SHELL",
+			fsys:   fstest.MapFS{},
+			runner: system.StaticRunner{"/bin/bash --version": bashVersion},
+			want:   Shell{Name: "bash", Version: "5.2.26(1)-release"},
+		},
+		{
+			name:   "$SHELL symlink to dash is followed",
+			fsys:   fstest.MapFS{},
+			links:  map[string]string{"bin/bash": "dash"},
+			runner: system.StaticRunner{"dpkg-query -W -f=${Version} dash": "0.5.12-6"},
+			want:   Shell{Name: "dash", Version: "0.5.12"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := system.FromFS(linkFS{tt.fsys, tt.links}).WithRunner(tt.runner).WithEnv(env(map[string]string{"SHELL": "/bin/bash"}))
+			got, err := GetShell(context.Background(), sys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("GetShell() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetShellCachesVersion(t *testing.T) {
+	dir := t.TempDir()
+	exe := &fstest.MapFile{Data: make([]byte, 900), ModTime: time.Unix(1700000000, 0)}
+	fsys := linkFS{
+		procTree(fstest.MapFS{"proc/999/exe": exe}, "asfetch", "nu", "wezterm-gui"),
+		map[string]string{"proc/999/exe": "/usr/bin/nu"},
+	}
+	shell := func(runner system.StaticRunner) Shell {
+		t.Helper()
+		got, err := GetShell(context.Background(), system.FromFS(fsys).WithRunner(runner).WithCacheDir(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	// Wpis innej powłoki musi przetrwać, a nieaktualne wpisy nu zniknąć przy zapisie.
+	seed := `{"zsh 800 1600000000":"5.9","nu 900 1600000000":"0.80.0"}`
+	if err := os.WriteFile(filepath.Join(dir, "shells.json"), []byte(seed), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	shell(system.StaticRunner{"/usr/bin/nu --version": "0.92.2\n"})
+
+	// Drugie uruchomienie nie może już wołać nu --version.
+	if got, want := shell(system.StaticRunner{}), (Shell{Name: "nu", Version: "0.92.2"}); got != want {
+		t.Errorf("GetShell() = %+v, want %+v", got, want)
+	}
+
+	// Po aktualizacji powłoki (inny plik wykonywalny) wersja jest odczytywana na nowo.
+	exe.ModTime = time.Unix(1800000000, 0)
+	if got := shell(system.StaticRunner{"/usr/bin/nu --version": "0.93.0\n"}); got.Version != "0.93.0" {
+		t.Errorf("Version after update = %q, want 0.93.0", got.Version)
+	}
+
+	// Zapis idzie przez plik tymczasowy, który po rename nie może zostać w katalogu.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "shells.json" {
+		t.Errorf("cache dir = %v, want only shells.json", entries)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "shells.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cache map[string]string
+	if err := json.Unmarshal(data, &cache); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"zsh 800 1600000000": "5.9", "nu 900 1800000000": "0.93.0"}
+	if !reflect.DeepEqual(cache, want) {
+		t.Errorf("shells.json = %v, want %v", cache, want)
+	}
+}
+
+func TestGetShellWithoutCacheDirWritesNothing(t *testing.T) {
+	// Bez CacheDir (domyślnie w bibliotece) nic nie trafia na dysk, nawet do $XDG_CACHE_HOME.
+	home := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", home)
+	fsys := linkFS{
+		procTree(fstest.MapFS{"proc/999/exe": {Data: make([]byte, 900)}}, "asfetch", "nu", "wezterm-gui"),
+		map[string]string{"proc/999/exe": "/usr/bin/nu"},
+	}
+	sys := system.FromFS(fsys).WithRunner(system.StaticRunner{"/usr/bin/nu --version": "0.92.2\n"})
+	if _, err := GetShell(context.Background(), sys); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(home); len(entries) != 0 {
+		t.Errorf("GetShell wrote %v without CacheDir", entries)
+	}
+}
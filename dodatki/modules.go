@@ -0,0 +1,28 @@
+package dodatki
+
+import (
+	"asf/modules"
+	"asf/system"
+	"context"
+)
+
+func cachedTerminal(ctx context.Context, sys *system.System) (Terminal, error) {
+	return modules.Cached(ctx, "dodatki.terminal", func(context.Context) (Terminal, error) {
+		return GetTerminal(sys)
+	})
+}
+
+func init() {
+	modules.Register(modules.Func("user", "User", modules.Data(GetUserAndHost)))
+	modules.Register(modules.Func("shell", "Shell", modules.DataContext(GetShell)))
+	modules.Register(modules.Func("terminal", "Terminal", modules.DataContext(cachedTerminal)))
+	modules.Register(modules.Func("terminal_font", "Terminal Font", modules.DataContext(func(ctx context.Context, sys *system.System) (TerminalFont, error) {
+		term, err := cachedTerminal(ctx, sys)
+		if err != nil {
+			return TerminalFont{}, err
+		}
+		return GetTerminalFont(sys, term)
+	})))
+	modules.Register(modules.Func("uptime", "Uptime", modules.Data(GetUptime)))
+	modules.Register(modules.Func("music", "Spotify", modules.DataContext(GetCurrentMusic)))
+}
@@ -1,22 +1,33 @@
 package dodatki
 
 import (
-	"os/exec"
+	"asf/modules"
+	"asf/system"
+	"context"
 	"strings"
 )
 
-func GetCurrentMusic() string {
-	if out, err := exec.Command("playerctl", "-p", "spotify", "metadata", "--format", "{{artist}} - {{title}}").Output(); err == nil {
-		if str := strings.TrimSpace(string(out)); str != "" {
-			return str
-		}
-	}
+type Track struct {
+	Artist string `json:"artist"`
+	Title  string `json:"title"`
+}
 
-	if out, err := exec.Command("playerctl", "metadata", "--format", "{{artist}} - {{title}}").Output(); err == nil {
-		if str := strings.TrimSpace(string(out)); str != "" {
-			return str
+const playerctlFormat = "{{artist}}\t{{title}}"
+
+func GetCurrentMusic(ctx context.Context, sys *system.System) (Track, error) {
+	for _, args := range [][]string{
+		{"-p", "spotify", "metadata", "--format", playerctlFormat},
+		{"metadata", "--format", playerctlFormat},
+	} {
+		out, err := sys.Output(ctx, "playerctl", args...)
+		if err != nil {
+			continue
+		}
+		artist, title, _ := strings.Cut(strings.TrimRight(string(out), "\r\n"), "\t")
+		track := Track{Artist: strings.TrimSpace(artist), Title: strings.TrimSpace(title)}
+		if track.Artist != "" || track.Title != "" {
+			return track, nil
 		}
 	}
-
-	return "-"
+	return Track{}, modules.ErrUnavailable
 }
@@ -0,0 +1,64 @@
+package dodatki
+
+import (
+	"asf/system"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// process to proces z /proc: numer, numer rodzica i nazwa polecenia (comm, do 15 znaków).
+type process struct {
+	pid  int
+	ppid int
+	comm string
+}
+
+// readProcess czyta /proc/<pid>/stat; pid "self" oznacza proces asfetch.
+func readProcess(sys *system.System, pid string) (process, error) {
+	data, err := sys.ReadFile("/proc/" + pid + "/stat")
+	if err != nil {
+		return process{}, err
+	}
+	// Nazwa w nawiasach może zawierać spacje i nawiasy, więc pola liczymy od ostatniego ')'.
+	stat := string(data)
+	open, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return process{}, fmt.Errorf("niepoprawny format /proc/%s/stat", pid)
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 2 {
+		return process{}, fmt.Errorf("niepoprawny format /proc/%s/stat", pid)
+	}
+
+	p := process{comm: stat[open+1 : end]}
+	if p.pid, err = strconv.Atoi(strings.TrimSpace(stat[:open])); err != nil {
+		return process{}, fmt.Errorf("niepoprawny format /proc/%s/stat: %w", pid, err)
+	}
+	if p.ppid, err = strconv.Atoi(fields[1]); err != nil {
+		return process{}, fmt.Errorf("niepoprawny format /proc/%s/stat: %w", pid, err)
+	}
+	if comm, err := sys.ReadFile("/proc/" + strconv.Itoa(p.pid) + "/comm"); err == nil {
+		p.comm = strings.TrimSpace(string(comm))
+	}
+	return p, nil
+}
+
+// ancestors zwraca przodków procesu asfetch, od rodzica aż do inita.
+func ancestors(sys *system.System) ([]process, error) {
+	self, err := readProcess(sys, "self")
+	if err != nil {
+		return nil, err
+	}
+
+	var chain []process
+	for pid := self.ppid; pid > 1 && len(chain) < 64; {
+		p, err := readProcess(sys, strconv.Itoa(pid))
+		if err != nil {
+			break
+		}
+		chain = append(chain, p)
+		pid = p.ppid
+	}
+	return chain, nil
+}
@@ -1,15 +1,184 @@
 package dodatki
 
 import (
+	"asf/modules"
+	"asf/system"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
-func GetShell() string {
-	shell := os.Getenv("SHELL")
-	if shell != "" {
-		parts := strings.Split(shell, "/")
-		return parts[len(parts)-1]
+type Shell struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// shells to nazwy procesów powłok rozpoznawanych w drzewie procesów.
+var shells = map[string]bool{
+	"bash": true, "zsh": true, "fish": true, "nu": true, "dash": true, "sh": true,
+	"ksh": true, "mksh": true, "tcsh": true, "csh": true, "elvish": true, "xonsh": true,
+}
+
+var (
+	reVersion = regexp.MustCompile(`\d+(?:\.\d+)+`)
+	// reBashVersion zachowuje numer poprawki i wydanie, np. "5.2.15(1)-release".
+	reBashVersion = regexp.MustCompile(`version (\S+)`)
+)
+
+// GetShell zwraca powłokę, z której uruchomiono asfetch (najbliższą powłokę wśród przodków),
+// a gdy nie da się jej znaleźć w /proc, powłokę logowania z $SHELL. Jeśli sys ma CacheDir,
+// wersja jest zapamiętywana w pliku podręcznym, dopóki nie zmieni się plik wykonywalny powłoki.
+func GetShell(ctx context.Context, sys *system.System) (Shell, error) {
+	name, exe := "", ""
+	if chain, err := ancestors(sys); err == nil {
+		for _, p := range chain {
+			if shells[p.comm] {
+				name, exe = p.comm, "/proc/"+strconv.Itoa(p.pid)+"/exe"
+				break
+			}
+		}
+	}
+	if name == "" {
+		shell := sys.Getenv("SHELL")
+		if shell == "" {
+			return Shell{}, modules.ErrUnavailable
+		}
+		name, exe = filepath.Base(shell), shell
+	}
+
+	// sh to zwykle dowiązanie do innej powłoki: dash w Debianie, bash w Arch i Fedorze,
+	// busybox w Alpine. O nazwie i wersji decyduje plik, na który wskazuje.
+	path := resolveExe(sys, exe)
+	if base := filepath.Base(path); shells[base] || base == "busybox" {
+		name = base
+	}
+
+	// Klucz zmienia się po aktualizacji powłoki, więc nieaktualna wersja nie zostaje w pamięci.
+	key := ""
+	if info, err := sys.Stat(exe); err == nil {
+		key = fmt.Sprintf("%s %d %d", name, info.Size(), info.ModTime().Unix())
+	}
+	cache := loadShellCache(sys)
+	if version, ok := cache[key]; ok && key != "" {
+		return Shell{Name: name, Version: version}, nil
+	}
+
+	version := shellVersion(ctx, sys, name, path)
+	if err := ctx.Err(); err != nil {
+		return Shell{}, err
+	}
+	if key != "" && version != "" {
+		// Wpisy tej samej powłoki dotyczą plików sprzed aktualizacji; bez ich usuwania plik
+		// rósłby z każdą aktualizacją.
+		maps.DeleteFunc(cache, func(k, _ string) bool { return strings.HasPrefix(k, name+" ") })
+		cache[key] = version
+		saveShellCache(sys, cache)
+	}
+	return Shell{Name: name, Version: version}, nil
+}
+
+// resolveExe podąża za dowiązaniami symbolicznymi (najwyżej kilkoma), żeby uruchomić dokładnie
+// ten plik, który działa jako powłoka, a nie program o tej samej nazwie znaleziony w PATH.
+// Gdy dowiązania nie da się odczytać, zwraca ścieżkę bez zmian (/proc/<pid>/exe też da się uruchomić).
+func resolveExe(sys *system.System, path string) string {
+	for range 8 {
+		target, err := sys.ReadLink(path)
+		if err != nil {
+			break
+		}
+		// Jądro dopisuje " (deleted)", gdy plik podmieniono po uruchomieniu (np. aktualizacja).
+		target = strings.TrimSuffix(target, " (deleted)")
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		path = target
+	}
+	return path
+}
+
+// shellVersion pyta plik powłoki o wersję. dash nie ma --version, więc jego wersję podaje menedżer
+// pakietów, a busybox przedstawia się w pierwszej linii --help.
+func shellVersion(ctx context.Context, sys *system.System, name, path string) string {
+	var commands [][]string
+	switch name {
+	case "dash":
+		commands = [][]string{{"dpkg-query", "-W", "-f=${Version}", "dash"}, {"pacman", "-Q", "dash"}}
+	case "busybox":
+		commands = [][]string{{path, "--help"}}
+	default:
+		commands = [][]string{{path, "--version"}}
+	}
+	for _, argv := range commands {
+		out, err := sys.Output(ctx, argv[0], argv[1:]...)
+		if err != nil && len(out) == 0 {
+			continue
+		}
+		if name == "bash" {
+			first, _, _ := bytes.Cut(out, []byte("\n"))
+			if m := reBashVersion.FindSubmatch(first); m != nil {
+				return string(m[1])
+			}
+		}
+		if version := reVersion.Find(out); version != nil {
+			return string(version)
+		}
+	}
+	return ""
+}
+
+// shellCachePath zwraca plik podręczny wersji powłok albo pusty napis, gdy wywołujący nie włączył
+// pamięci podręcznej (system.System.CacheDir).
+func shellCachePath(sys *system.System) string {
+	if sys.CacheDir == "" {
+		return ""
+	}
+	return filepath.Join(sys.CacheDir, "shells.json")
+}
+
+func loadShellCache(sys *system.System) map[string]string {
+	cache := map[string]string{}
+	if path := shellCachePath(sys); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, &cache)
+		}
+	}
+	return cache
+}
+
+// saveShellCache zapisuje plik podręczny przez plik tymczasowy i rename, więc równoległe
+// uruchomienia nie zobaczą połowy pliku. Błędy są pomijane, bo w najgorszym razie wersja
+// zostanie odczytana ponownie przy następnym uruchomieniu.
+func saveShellCache(sys *system.System, cache map[string]string) {
+	path := shellCachePath(sys)
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "shells-*.json")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
 	}
-	return "unknown"
 }
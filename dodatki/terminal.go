@@ -0,0 +1,193 @@
+package dodatki
+
+import (
+	"asf/modules"
+	"asf/system"
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Terminal to emulator terminala, w którym działa asfetch.
+type Terminal struct {
+	Name string `json:"name"`
+	// Process to nazwa procesu, po której rozpoznano terminal (np. "gnome-terminal-").
+	Process string `json:"process"`
+}
+
+// TerminalFont to czcionka z pliku konfiguracyjnego terminala; Size równe 0 oznacza rozmiar domyślny.
+type TerminalFont struct {
+	Family string  `json:"family"`
+	Size   float64 `json:"size,omitempty"`
+}
+
+// terminals tłumaczy nazwy procesów (comm, obcięte do 15 znaków) na nazwy terminali.
+var terminals = map[string]string{
+	"alacritty":       "Alacritty",
+	"foot":            "foot",
+	"footclient":      "foot",
+	"ghostty":         "Ghostty",
+	"gnome-terminal-": "GNOME Terminal",
+	"kgx":             "GNOME Console",
+	"kitty":           "kitty",
+	"konsole":         "Konsole",
+	"screen":          "screen",
+	"sshd":            "SSH",
+	"st":              "st",
+	"terminator":      "Terminator",
+	"tilix":           "Tilix",
+	"tmux: server":    "tmux",
+	"urxvt":           "urxvt",
+	"urxvtd":          "urxvt",
+	"wezterm-gui":     "WezTerm",
+	"xfce4-terminal":  "Xfce Terminal",
+	"xterm":           "xterm",
+}
+
+// GetTerminal idzie w górę drzewa procesów od asfetch i zwraca pierwszy rozpoznany terminal.
+// Sesje tmux i screen są osobnymi serwerami bez terminala w przodkach, więc zwracany jest sam multiplekser.
+func GetTerminal(sys *system.System) (Terminal, error) {
+	if runtime.GOOS != "linux" {
+		return Terminal{}, modules.ErrUnavailable
+	}
+
+	chain, err := ancestors(sys)
+	if err != nil {
+		return Terminal{}, err
+	}
+	for _, p := range chain {
+		if name, ok := terminals[p.comm]; ok {
+			return Terminal{Name: name, Process: p.comm}, nil
+		}
+	}
+
+	// Terminale spoza listy często same się przedstawiają (WezTerm, vscode, Apple_Terminal).
+	if program := sys.Getenv("TERM_PROGRAM"); program != "" {
+		return Terminal{Name: program}, nil
+	}
+	return Terminal{}, modules.ErrUnavailable
+}
+
+var (
+	reWezTermFont     = regexp.MustCompile(`\bfont\s*=\s*wezterm\.font(?:_with_fallback)?\s*\(\s*\{?\s*['"]([^'"]+)['"]`)
+	reWezTermFontSize = regexp.MustCompile(`\bfont_size\s*=\s*([\d.]+)`)
+)
+
+// GetTerminalFont czyta czcionkę z pliku konfiguracyjnego wykrytego terminala. Obsługiwane są
+// kitty, Alacritty, foot, WezTerm i Ghostty; pozostałe terminale trzymają ustawienia w dconf
+// albo profilach, których nie czytamy.
+func GetTerminalFont(sys *system.System, term Terminal) (TerminalFont, error) {
+	var font TerminalFont
+	switch term.Name {
+	case "kitty":
+		font = keyValueFont(readConfig(sys, "kitty/kitty.conf"), "", "", "font_family", "font_size")
+	case "Alacritty":
+		font = alacrittyFont(readConfig(sys, "alacritty/alacritty.toml"))
+	case "foot":
+		font = footFont(readConfig(sys, "foot/foot.ini"))
+	case "Ghostty":
+		font = keyValueFont(readConfig(sys, "ghostty/config"), "=", "", "font-family", "font-size")
+	case "WezTerm":
+		data := readConfig(sys, "wezterm/wezterm.lua")
+		if data == nil {
+			if home := sys.Getenv("HOME"); home != "" {
+				data, _ = sys.ReadFile(filepath.Join(home, ".wezterm.lua"))
+			}
+		}
+		if m := reWezTermFont.FindSubmatch(data); m != nil {
+			font.Family = string(m[1])
+		}
+		if m := reWezTermFontSize.FindSubmatch(data); m != nil {
+			font.Size, _ = strconv.ParseFloat(string(m[1]), 64)
+		}
+	}
+	if font.Family == "" {
+		return TerminalFont{}, modules.ErrUnavailable
+	}
+	return font, nil
+}
+
+// keyValueFont czyta pliki w formacie "klucz<sep>wartość" z komentarzami '#'; section ogranicza
+// odczyt do sekcji [section] (pusty oznacza cały plik).
+func keyValueFont(data []byte, sep, section, familyKey, sizeKey string) TerminalFont {
+	var font TerminalFont
+	current := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.Trim(line, "[] ")
+			continue
+		}
+		if current != section {
+			continue
+		}
+		// Pusty sep oznacza klucz oddzielony dowolnymi białymi znakami (kitty.conf).
+		i := strings.IndexAny(line, " \t")
+		if sep != "" {
+			i = strings.Index(line, sep)
+		}
+		if i < 0 {
+			continue
+		}
+		key, value := line[:i], line[i+max(len(sep), 1):]
+		key, value = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), `"'`)
+		switch key {
+		case familyKey:
+			font.Family = value
+		case sizeKey:
+			font.Size, _ = strconv.ParseFloat(value, 64)
+		}
+	}
+	return font
+}
+
+// alacrittyFont czyta [font.normal] family i [font] size z alacritty.toml.
+func alacrittyFont(data []byte) TerminalFont {
+	return TerminalFont{
+		Family: keyValueFont(data, "=", "font.normal", "family", "").Family,
+		Size:   keyValueFont(data, "=", "font", "", "size").Size,
+	}
+}
+
+// footFont czyta font=Nazwa:size=11 z sekcji [main] (albo sprzed pierwszej sekcji) foot.ini.
+// Przy kilku czcionkach po przecinku liczy się pierwsza.
+func footFont(data []byte) TerminalFont {
+	spec, _, _ := strings.Cut(keyValueFont(data, "=", "", "font", "").Family, ",")
+	if spec == "" {
+		spec, _, _ = strings.Cut(keyValueFont(data, "=", "main", "font", "").Family, ",")
+	}
+	family, attrs, _ := strings.Cut(spec, ":")
+	font := TerminalFont{Family: strings.TrimSpace(family)}
+	for _, attr := range strings.Split(attrs, ":") {
+		if size, ok := strings.CutPrefix(strings.TrimSpace(attr), "size="); ok {
+			font.Size, _ = strconv.ParseFloat(size, 64)
+		}
+	}
+	return font
+}
+
+// readConfig czyta plik z katalogu konfiguracji użytkownika ($XDG_CONFIG_HOME albo $HOME/.config).
+// Bez środowiska sesji (obcy korzeń) katalog jest nieznany i zwracane jest nil.
+func readConfig(sys *system.System, name string) []byte {
+	dir := sys.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home := sys.Getenv("HOME")
+		if home == "" {
+			return nil
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	data, err := sys.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -1,41 +1,31 @@
 package dodatki
 
 import (
+	"asf/modules"
+	"asf/system"
 	"fmt"
-	"io/ioutil"
 	"runtime"
 	"strconv"
 	"strings"
 	"time"
 )
 
-func GetUptime() string {
-	if runtime.GOOS == "linux" {
-		if data, err := ioutil.ReadFile("/proc/uptime"); err == nil {
-			parts := strings.Fields(string(data))
-			if len(parts) > 0 {
-				uptimeSeconds, _ := strconv.ParseFloat(parts[0], 64)
-				return FormatDuration(time.Duration(uptimeSeconds) * time.Second)
-			}
-		}
+func GetUptime(sys *system.System) (time.Duration, error) {
+	if runtime.GOOS != "linux" {
+		return 0, modules.ErrUnavailable
 	}
-	return "unknown"
-}
-
-func FormatDuration(d time.Duration) string {
-	days := int(d.Hours() / 24)
-	hours := int(d.Hours()) % 24
-	minutes := int(d.Minutes()) % 60
 
-	var parts []string
-	if days > 0 {
-		parts = append(parts, fmt.Sprintf("%d dni", days))
+	data, err := sys.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, fmt.Errorf("nie udało się odczytać /proc/uptime: %w", err)
 	}
-	if hours > 0 {
-		parts = append(parts, fmt.Sprintf("%d godz.", hours))
+	parts := strings.Fields(string(data))
+	if len(parts) == 0 {
+		return 0, fmt.Errorf("pusty plik /proc/uptime")
 	}
-	if minutes > 0 || len(parts) == 0 { // Upewnij się, że "0 min" jest wyświetlane, jeśli czas jest krótszy niż godzina
-		parts = append(parts, fmt.Sprintf("%d min", minutes))
+	uptimeSeconds, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("niepoprawny format /proc/uptime: %w", err)
 	}
-	return strings.Join(parts, ", ")
+	return time.Duration(uptimeSeconds) * time.Second, nil
 }
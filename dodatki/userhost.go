@@ -1,14 +1,26 @@
 package dodatki
 
 import (
-	"os"
+	"asf/system"
+	"strings"
 )
 
-func GetUserAndHost() (string, string) {
-	username := os.Getenv("USER")
-	hostname, err := os.Hostname()
-	if err != nil {
-		hostname = "unknown"
+type UserHost struct {
+	User string `json:"user"`
+	Host string `json:"host"`
+}
+
+// GetUserAndHost zwraca użytkownika sesji i nazwę hosta. Dla obcego korzenia użytkownik
+// jest nieznany (pusty), bo $USER opisuje sesję asfetch, a nie badany system.
+func GetUserAndHost(sys *system.System) (UserHost, error) {
+	hostname := "unknown"
+	for _, path := range []string{"/proc/sys/kernel/hostname", "/etc/hostname"} {
+		if data, err := sys.ReadFile(path); err == nil {
+			if name := strings.TrimSpace(string(data)); name != "" {
+				hostname = name
+				break
+			}
+		}
 	}
-	return username, hostname
+	return UserHost{User: sys.Getenv("USER"), Host: hostname}, nil
 }
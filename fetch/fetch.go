@@ -0,0 +1,150 @@
+// Package fetch to publiczne API do osadzania asfetch w innych programach.
+// Nie wypisuje niczego na stdout/stderr i nie trzyma stanu między wywołaniami Collect
+// (chyba że wywołujący poda Options.CacheDir).
+package fetch
+
+import (
+	"asf/modules"
+	"asf/present"
+	"asf/system"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "asf/desktop"
+	_ "asf/dodatki"
+	_ "asf/hardware"
+	_ "asf/osinfo"
+)
+
+type Options struct {
+	// Modules to nazwy modułów w kolejności wyświetlania; nil oznacza DefaultModules().
+	Modules []string
+	// Timeout to łączny limit czasu na wszystkie moduły; zero oznacza brak limitu
+	// (poza tym, co narzuca przekazany kontekst).
+	Timeout time.Duration
+	// ModuleTimeouts nadpisuje limit czasu dla wybranych modułów.
+	ModuleTimeouts map[string]time.Duration
+	// ModuleOptions to ustawienia wybranych modułów kluczowane nazwą modułu,
+	// np. {"disk": hardware.DiskOptions{...}}.
+	ModuleOptions map[string]any
+	// Sysroot to katalog, względem którego czytane są /proc, /sys, /etc itd.;
+	// pusty oznacza system, na którym działa program.
+	Sysroot string
+	// System, jeśli ustawiony, ma pierwszeństwo przed Sysroot (np. system.FromFS w testach).
+	System *system.System
+	// Runner, jeśli ustawiony, zastępuje wykonawcę poleceń systemu (np. system.Replayer).
+	Runner system.Runner
+	// CacheDir włącza pliki podręczne między wywołaniami (np. wersje powłok) w podanym katalogu.
+	// Pusty oznacza brak zapisów na dysk. Jest używany tylko dla systemu, na którym działa program,
+	// bo wyniki dla --sysroot opisują inną maszynę.
+	CacheDir string
+}
+
+type Entry struct {
+	Name  string
+	Label string
+	// Data to typowane dane modułu, np. hardware.CPUInfo albo time.Duration.
+	Data any
+	// Err jest ustawiony, gdy moduł zawiódł, nie ma danych (modules.ErrUnavailable)
+	// albo przekroczył limit czasu (context.DeadlineExceeded).
+	Err error
+}
+
+func (e Entry) TimedOut() bool {
+	return errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+func (e Entry) Available() bool {
+	return e.Err == nil
+}
+
+// Text zwraca dane w takiej postaci, w jakiej pokazuje je tabela asfetch.
+func (e Entry) Text() string {
+	return present.Text(e.Data)
+}
+
+type Report struct {
+	Entries []Entry
+}
+
+// DefaultModules zwraca domyślną listę modułów w domyślnej kolejności.
+func DefaultModules() []string {
+	return []string{
+		"user",
+		"os",
+		"kernel",
+		"packages",
+		"de",
+		"wm",
+		"uptime",
+		"cpu",
+		"gpu",
+		"ram",
+		"swap",
+		"music",
+	}
+}
+
+// Available zwraca nazwy wszystkich dostępnych modułów.
+func Available() []string {
+	return modules.Names()
+}
+
+// Known sprawdza, czy moduł o podanej nazwie istnieje.
+func Known(name string) bool {
+	_, ok := modules.Lookup(name)
+	return ok
+}
+
+// Collect uruchamia wybrane moduły równolegle i zwraca ich wyniki w kolejności z opts.Modules.
+// Błąd zwracany jest tylko dla niepoprawnych opcji; błędy poszczególnych modułów trafiają do Entry.Err.
+func Collect(ctx context.Context, opts Options) (Report, error) {
+	names := opts.Modules
+	if names == nil {
+		names = DefaultModules()
+	}
+
+	mods := make([]modules.Module, 0, len(names))
+	for _, name := range names {
+		mod, ok := modules.Lookup(name)
+		if !ok {
+			return Report{}, fmt.Errorf("nieznany moduł %q", name)
+		}
+		mods = append(mods, mod)
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	sys := opts.System
+	if sys == nil {
+		sys = system.New(opts.Sysroot)
+	}
+	if opts.Runner != nil {
+		sys = sys.WithRunner(opts.Runner)
+	}
+	if opts.CacheDir != "" && sys.Root() == "/" {
+		sys = sys.WithCacheDir(opts.CacheDir)
+	}
+
+	ctx = modules.WithCache(modules.WithOptions(ctx, opts.ModuleOptions))
+	outcomes := modules.Run(ctx, sys, mods, func(name string) time.Duration {
+		return opts.ModuleTimeouts[name]
+	})
+
+	report := Report{Entries: make([]Entry, 0, len(outcomes))}
+	for _, out := range outcomes {
+		report.Entries = append(report.Entries, Entry{
+			Name:  out.Module.Name(),
+			Label: out.Module.Label(),
+			Data:  out.Result.Data,
+			Err:   out.Err,
+		})
+	}
+	return report, nil
+}
@@ -0,0 +1,223 @@
+package fetch
+
+import (
+	"asf/modules"
+	"asf/system"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// Moduł test_cache_dir pokazuje, jaki CacheDir dostały moduły, żeby sprawdzić go bez pisania na dysk.
+func init() {
+	modules.Register(modules.Func("test_cache_dir", "Cache", func(_ context.Context, sys *system.System) (modules.Result, error) {
+		return modules.Result{Data: sys.CacheDir}, nil
+	}))
+}
+
+var testFS = fstest.MapFS{
+	"proc/sys/kernel/osrelease": {Data: []byte("6.8.1-arch1-1\n")},
+	"proc/uptime":               {Data: []byte("3600.00 7000.00\n")},
+	"etc/hostname":              {Data: []byte("nora\n")},
+}
+
+// hangingRunner zawiesza playerctl do końca kontekstu, jak odtwarzacz, który nie odpowiada
+// na D-Bus; pozostałe polecenia obsługuje StaticRunner.
+type hangingRunner struct {
+	system.StaticRunner
+}
+
+func (r hangingRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if name == "playerctl" {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return r.StaticRunner.Run(ctx, name, args...)
+}
+
+func entry(t *testing.T, report Report, name string) Entry {
+	t.Helper()
+	for _, e := range report.Entries {
+		if e.Name == name {
+			return e
+		}
+	}
+	t.Fatalf("brak modułu %q w raporcie", name)
+	return Entry{}
+}
+
+func TestCollectKeepsModuleOrder(t *testing.T) {
+	names := []string{"uptime", "user", "kernel", "music"}
+	report, err := Collect(context.Background(), Options{Modules: names, System: system.FromFS(testFS)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, e := range report.Entries {
+		got = append(got, e.Name)
+	}
+	if !slices.Equal(got, names) {
+		t.Errorf("kolejność = %v, want %v", got, names)
+	}
+	if e := entry(t, report, "kernel"); e.Text() != "6.8.1-arch1-1" {
+		t.Errorf("kernel = %q, %v", e.Text(), e.Err)
+	}
+	if e := entry(t, report, "music"); e.Available() || e.TimedOut() {
+		t.Errorf("music bez playerctl = %+v, want niedostępny", e)
+	}
+}
+
+func TestCollectUnknownModule(t *testing.T) {
+	if _, err := Collect(context.Background(), Options{Modules: []string{"kernel", "nie-ma"}, System: system.FromFS(testFS)}); err == nil {
+		t.Error("expected error for unknown module")
+	}
+}
+
+func TestCollectTimeouts(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"module timeout", Options{ModuleTimeouts: map[string]time.Duration{"music": 20 * time.Millisecond}}},
+		{"total timeout", Options{Timeout: 20 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Modules = []string{"kernel", "music", "uptime"}
+			opts.System = system.FromFS(testFS).WithRunner(hangingRunner{})
+
+			start := time.Now()
+			report, err := Collect(context.Background(), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("Collect trwał %v, zawieszony moduł zablokował pozostałe", elapsed)
+			}
+			if e := entry(t, report, "music"); !e.TimedOut() {
+				t.Errorf("music = %+v, want przekroczony czas", e)
+			}
+			for _, name := range []string{"kernel", "uptime"} {
+				if e := entry(t, report, name); !e.Available() {
+					t.Errorf("%s = %+v, want dostępny", name, e)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectSystemOverrides(t *testing.T) {
+	sysroot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(sysroot, "proc", "sys", "kernel"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sysroot, "proc", "sys", "kernel", "osrelease"), []byte("6.1.0-18-amd64\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Bez osrelease jądro pochodzi z uname, czyli z wykonawcy poleceń.
+	noRelease := system.FromFS(fstest.MapFS{}).WithRunner(system.StaticRunner{"uname -r": "1.0-system\n"})
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"sysroot", Options{Sysroot: sysroot}, "6.1.0-18-amd64"},
+		{"system wins over sysroot", Options{Sysroot: sysroot, System: system.FromFS(testFS)}, "6.8.1-arch1-1"},
+		{"system runner", Options{System: noRelease}, "1.0-system"},
+		{"runner replaces system runner", Options{System: noRelease, Runner: system.StaticRunner{"uname -r": "2.0-runner\n"}}, "2.0-runner"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Modules = []string{"kernel"}
+			report, err := Collect(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e := entry(t, report, "kernel"); e.Text() != tt.want {
+				t.Errorf("kernel = %q (%v), want %q", e.Text(), e.Err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectCacheDirOnlyForHost(t *testing.T) {
+	// System z korzeniem "/", ale z plikami testowymi, żeby nie dotykać prawdziwego hosta.
+	host := system.Host()
+	host.FS = testFS
+
+	tests := []struct {
+		name string
+		sys  *system.System
+		want string
+	}{
+		{"host", host, "/tmp/asf-cache"},
+		{"fs without root", system.FromFS(testFS), ""},
+		{"sysroot", system.New(t.TempDir()), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Collect(context.Background(), Options{
+				Modules:  []string{"test_cache_dir"},
+				System:   tt.sys,
+				CacheDir: "/tmp/asf-cache",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := entry(t, report, "test_cache_dir").Data; got != tt.want {
+				t.Errorf("CacheDir = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// captureOutput przekierowuje stdout i stderr procesu na czas fn i zwraca, co zostało wypisane.
+func captureOutput(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = w, w
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(&buf, r)
+		close(done)
+	}()
+	fn()
+	w.Close()
+	<-done
+	return buf.String()
+}
+
+func TestCollectIsSilent(t *testing.T) {
+	out := captureOutput(t, func() {
+		// Domyślne moduły na prawie pustym systemie: większość zawiedzie, ale błędy trafiają do Entry.Err.
+		sys := system.FromFS(testFS).WithRunner(hangingRunner{})
+		if _, err := Collect(context.Background(), Options{System: sys, Timeout: 50 * time.Millisecond}); err != nil {
+			t.Error(err)
+		}
+		if _, err := Collect(context.Background(), Options{Modules: []string{"nie-ma"}, System: sys}); err == nil {
+			t.Error("expected error for unknown module")
+		}
+	})
+	if out != "" {
+		t.Errorf("Collect wypisał:\n%s", out)
+	}
+}
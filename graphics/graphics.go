@@ -0,0 +1,183 @@
+// Package graphics rysuje obrazki w terminalu protokołami graficznymi (Kitty, Sixel, iTerm2),
+// tak żeby zajmowały z góry znaną liczbę komórek.
+package graphics
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Protocol int
+
+const (
+	// HalfBlock i Braille nie są protokołami graficznymi, tylko zamianą obrazka na tekst
+	// dla terminali, które grafiki nie obsługują.
+	HalfBlock Protocol = iota
+	Braille
+	Kitty
+	Sixel
+	ITerm2
+)
+
+func (p Protocol) String() string {
+	switch p {
+	case Kitty:
+		return "kitty"
+	case Sixel:
+		return "sixel"
+	case ITerm2:
+		return "iterm2"
+	case Braille:
+		return "braille"
+	}
+	return "halfblock"
+}
+
+// ParseProtocol odczytuje sposób rysowania obrazka z konfiguracji; "auto" i pusty napis
+// oznaczają wykrywanie.
+func ParseProtocol(s string, getenv func(string) string) (Protocol, error) {
+	switch strings.ToLower(s) {
+	case "", "auto":
+		return Detect(getenv), nil
+	case "kitty":
+		return Kitty, nil
+	case "sixel":
+		return Sixel, nil
+	case "iterm2":
+		return ITerm2, nil
+	case "halfblock":
+		return HalfBlock, nil
+	case "braille":
+		return Braille, nil
+	}
+	return HalfBlock, fmt.Errorf("nieznany sposób rysowania obrazka %q (dostępne: auto, kitty, sixel, iterm2, halfblock, braille)", s)
+}
+
+// Detect zgaduje protokół na podstawie zmiennych środowiskowych ustawianych przez terminale.
+// Gdy żaden nie pasuje, obrazek będzie rysowany półblokami.
+func Detect(getenv func(string) string) Protocol {
+	termName := getenv("TERM")
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "" || termName == "xterm-kitty":
+		return Kitty
+	case getenv("TERM_PROGRAM") == "ghostty" || termName == "xterm-ghostty":
+		return Kitty
+	case getenv("KONSOLE_VERSION") != "":
+		return Kitty
+	case getenv("TERM_PROGRAM") == "iTerm.app" || getenv("TERM_PROGRAM") == "WezTerm":
+		return ITerm2
+	case strings.HasPrefix(termName, "foot"), strings.Contains(termName, "mlterm"),
+		getenv("TERM_PROGRAM") == "contour":
+		return Sixel
+	}
+	return HalfBlock
+}
+
+// IsImageFile mówi, czy ścieżka wskazuje obrazek, który umiemy zdekodować.
+func IsImageFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg", ".gif":
+		return true
+	}
+	return false
+}
+
+// Image to zdekodowany obrazek razem z oryginalnymi bajtami pliku.
+type Image struct {
+	image.Image
+	Format string
+	Data   []byte
+}
+
+func Load(path string) (*Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("nie udało się wczytać obrazka %s: %w", path, err)
+	}
+	return Decode(data)
+}
+
+func Decode(data []byte) (*Image, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("nie udało się zdekodować obrazka: %w", err)
+	}
+	return &Image{Image: img, Format: format, Data: data}, nil
+}
+
+// Rows zwraca liczbę wierszy, które obrazek zajmie przy szerokości cols komórek,
+// z zachowaniem proporcji przy podanym rozmiarze komórki w pikselach.
+func (img *Image) Rows(cols, cellW, cellH int) int {
+	b := img.Bounds()
+	if b.Dx() == 0 || cols <= 0 || cellH <= 0 {
+		return 0
+	}
+	rows := (cols*cellW*b.Dy() + b.Dx()*cellH/2) / (b.Dx() * cellH)
+	return max(rows, 1)
+}
+
+// Logo zwraca linie logo zajmujące cols×rows komórek. Dla HalfBlock i Braille to po prostu
+// obrazek zamieniony na tekst (colored == false daje Braille bez kolorów, niezależnie od p).
+// Dla protokołów graficznych pierwsza linia zawiera sekwencję rysującą obrazek, a wszystkie są
+// wypełnione spacjami, żeby kolumna z informacjami zaczynała się za obrazkiem.
+func (img *Image) Logo(p Protocol, cols, cellW, cellH int, colored bool) ([]string, error) {
+	rows := img.Rows(cols, cellW, cellH)
+	if rows == 0 {
+		return nil, fmt.Errorf("pusty obrazek")
+	}
+
+	if !colored || p == Braille {
+		return braille(img.Image, cols, rows, colored), nil
+	}
+
+	var seq string
+	var err error
+	switch p {
+	case Kitty:
+		seq, err = img.kitty(cols, rows)
+	case ITerm2:
+		seq = img.iterm2(cols, rows)
+	case Sixel:
+		seq = img.sixel(cols*cellW, rows*cellH)
+	default:
+		return halfBlocks(img.Image, cols, rows), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	blank := strings.Repeat(" ", cols)
+	lines := make([]string, rows)
+	for i := range lines {
+		lines[i] = blank
+	}
+	// Najpierw robimy miejsce (żeby rysowanie nie przewinęło ekranu), potem rysujemy obrazek
+	// od bieżącej pozycji i wracamy kursorem tam, gdzie zaczyna się pierwsza linia. IND, w odróżnieniu
+	// od "\n", nie wraca do kolumny 0, więc obrazek trafia tam, gdzie układ postawił logo
+	// (za tabelą w układzie z logo po prawej, za wcięciem z lewej).
+	lines[0] = reserveRows(rows-1) + "\0337" + seq + "\0338" + blank
+	return lines, nil
+}
+
+// reserveRows przesuwa kursor n wierszy w dół (przewijając ekran, jeśli trzeba) i z powrotem,
+// nie zmieniając kolumny.
+func reserveRows(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat("\033D", n) + cursorUp(n)
+}
+
+func cursorUp(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("\033[%dA", n)
+}
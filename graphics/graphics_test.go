@@ -0,0 +1,265 @@
+package graphics
+
+import (
+	"asf/term"
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func solid(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want Protocol
+	}{
+		{map[string]string{"TERM": "xterm-kitty"}, Kitty},
+		{map[string]string{"KITTY_WINDOW_ID": "1", "TERM": "xterm-256color"}, Kitty},
+		{map[string]string{"TERM_PROGRAM": "WezTerm"}, ITerm2},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, ITerm2},
+		{map[string]string{"TERM": "foot"}, Sixel},
+		{map[string]string{"TERM": "foot-extra"}, Sixel},
+		{map[string]string{"TERM": "xterm-256color"}, HalfBlock},
+		{nil, HalfBlock},
+	}
+	for _, tt := range tests {
+		getenv := func(key string) string { return tt.env[key] }
+		if got := Detect(getenv); got != tt.want {
+			t.Errorf("Detect(%v) = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestParseProtocol(t *testing.T) {
+	getenv := func(key string) string {
+		if key == "TERM" {
+			return "foot"
+		}
+		return ""
+	}
+	for spec, want := range map[string]Protocol{"": Sixel, "auto": Sixel, "kitty": Kitty, "iTerm2": ITerm2, "halfblock": HalfBlock, "braille": Braille} {
+		got, err := ParseProtocol(spec, getenv)
+		if err != nil || got != want {
+			t.Errorf("ParseProtocol(%q) = %v, %v; want %v", spec, got, err, want)
+		}
+	}
+	if _, err := ParseProtocol("ascii", getenv); err == nil {
+		t.Error("oczekiwano błędu")
+	}
+}
+
+func TestRows(t *testing.T) {
+	img := &Image{Image: solid(200, 100, color.Black)}
+	// 20 kolumn × 8 px = 160 px szerokości, więc 80 px wysokości = 5 wierszy po 16 px.
+	if got := img.Rows(20, 8, 16); got != 5 {
+		t.Errorf("Rows = %d, want 5", got)
+	}
+	if got := img.Rows(1, 8, 16); got != 1 {
+		t.Errorf("Rows dla bardzo wąskiego obrazka = %d, want 1", got)
+	}
+}
+
+func TestLogoReservesCells(t *testing.T) {
+	img, err := Decode(encodePNG(t, solid(40, 40, color.NRGBA{255, 0, 0, 255})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []Protocol{Kitty, Sixel, ITerm2, HalfBlock, Braille} {
+		lines, err := img.Logo(p, 10, 8, 16, true)
+		if err != nil {
+			t.Fatalf("%v: %v", p, err)
+		}
+		if len(lines) != 5 {
+			t.Errorf("%v: %d linii, want 5", p, len(lines))
+		}
+		for i, line := range lines {
+			if strings.ContainsAny(line, "\r\n") {
+				t.Errorf("%v: linia %d zawiera znak nowej linii, który przestawiłby kursor do kolumny 0", p, i)
+			}
+			if w := term.Width(line); w != 10 {
+				t.Errorf("%v: linia %d ma szerokość %d, want 10", p, i, w)
+			}
+		}
+		graphic := p != HalfBlock && p != Braille
+		if reserve := "\033D\033D\033D\033D\033[4A\0337"; graphic && !strings.HasPrefix(lines[0], reserve) {
+			t.Errorf("%v: pierwsza linia %q, want prefiks %q", p, lines[0], reserve)
+		}
+		if saved := strings.Contains(lines[0], "\0337") && strings.Contains(lines[0], "\0338"); saved != graphic {
+			t.Errorf("%v: zapis/przywrócenie kursora = %v, want %v", p, saved, graphic)
+		}
+	}
+}
+
+func TestLogoWithoutColorIsPlainBraille(t *testing.T) {
+	img := &Image{Image: solid(8, 8, color.White)}
+	lines, err := img.Logo(Kitty, 4, 8, 16, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range lines {
+		if strings.ContainsRune(line, '\033') || strings.Trim(line, "⣿") != "" {
+			t.Errorf("linia %q, want same pełne znaki Braille'a bez kolorów", line)
+		}
+	}
+}
+
+func TestKittyChunksAndReencodes(t *testing.T) {
+	var buf bytes.Buffer
+	noisy := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for i := range noisy.Pix {
+		noisy.Pix[i] = uint8(i * 7919 % 251)
+	}
+	if err := jpeg.Encode(&buf, noisy, nil); err != nil {
+		t.Fatal(err)
+	}
+	img, err := Decode(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seq, err := img.kitty(10, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := strings.Split(strings.TrimSuffix(seq, "\033\\"), "\033\\")
+	if len(chunks) < 2 {
+		t.Fatalf("oczekiwano kilku fragmentów, jest %d", len(chunks))
+	}
+	if !strings.HasPrefix(chunks[0], "\033_Ga=T,f=100,q=2,C=1,c=10,r=5,m=1;") {
+		t.Errorf("pierwszy fragment: %.60q", chunks[0])
+	}
+	if !strings.HasPrefix(chunks[len(chunks)-1], "\033_Gm=0;") {
+		t.Errorf("ostatni fragment: %.60q", chunks[len(chunks)-1])
+	}
+
+	var payload strings.Builder
+	for _, c := range chunks {
+		_, data, _ := strings.Cut(c, ";")
+		if len(data) > kittyChunk {
+			t.Errorf("fragment dłuższy niż %d", kittyChunk)
+		}
+		payload.WriteString(data)
+	}
+	data, err := base64.StdEncoding.DecodeString(payload.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+		t.Errorf("JPEG nie został przekodowany na PNG: %v", err)
+	}
+}
+
+func TestITerm2(t *testing.T) {
+	data := encodePNG(t, solid(2, 2, color.White))
+	img, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "\033]1337;File=inline=1;size=" + strconv.Itoa(len(data)) + ";width=4;height=2;preserveAspectRatio=0:" +
+		base64.StdEncoding.EncodeToString(data) + "\a"
+	if got := img.iterm2(4, 2); got != want {
+		t.Errorf("iterm2 = %q, want %q", got, want)
+	}
+}
+
+func TestSixel(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 6))
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 4; x++ {
+			if x < 2 {
+				src.Set(x, y, color.NRGBA{255, 0, 0, 255})
+			} else if y < 3 {
+				src.Set(x, y, color.NRGBA{0, 0, 255, 255})
+			}
+		}
+	}
+	img := &Image{Image: src}
+	seq := img.sixel(4, 6)
+
+	if !strings.HasPrefix(seq, "\033P0;1;0q\"1;1;4;6#0;2;0;0;0") || !strings.HasSuffix(seq, "\033\\") {
+		t.Fatalf("nagłówek albo zakończenie: %.40q … %q", seq, seq[len(seq)-4:])
+	}
+	// Czerwony (#180) w dwóch pierwszych kolumnach na całej wysokości, niebieski (#5)
+	// w dwóch ostatnich, tylko w górnych trzech wierszach; reszta przezroczysta.
+	if body := seq[strings.LastIndex(seq, "#215;2;100;100;100")+len("#215;2;100;100;100") : len(seq)-2]; body != "#180~~??$#5??FF-" {
+		t.Errorf("dane sixel = %q", body)
+	}
+}
+
+func TestWriteRLE(t *testing.T) {
+	var b strings.Builder
+	writeRLE(&b, []byte("~~~~~??A@@@"))
+	if got := b.String(); got != "!5~??A@@@" {
+		t.Errorf("writeRLE = %q", got)
+	}
+}
+
+func TestScaleAverages(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.NRGBA{200, 0, 0, 255})
+	src.Set(1, 0, color.NRGBA{0, 0, 0, 0})
+	got := Scale(src, 1, 1).NRGBAAt(0, 0)
+	if got.R != 200 || got.A != 127 {
+		t.Errorf("Scale = %+v, want czerwony półprzezroczysty", got)
+	}
+}
+
+func TestHalfBlocks(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	red, blue := color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}
+	src.Set(0, 0, red)
+	src.Set(0, 1, blue)
+	src.Set(1, 0, red)
+	src.Set(2, 1, blue)
+
+	got := halfBlocks(src, 3, 1)
+	want := "\033[38;2;255;0;0m\033[48;2;0;0;255m▀" +
+		"\033[49m\033[38;2;255;0;0m▀" +
+		"\033[49m\033[38;2;0;0;255m▄" +
+		"\033[0m"
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("halfBlocks = %q, want %q", got, want)
+	}
+}
+
+func TestBraille(t *testing.T) {
+	// Jasna lewa kolumna kropek na ciemnym tle: bity 1, 2, 4 i 0x40.
+	src := solid(2, 4, color.NRGBA{0, 0, 0, 255})
+	for y := 0; y < 4; y++ {
+		src.Set(0, y, color.NRGBA{255, 255, 255, 255})
+	}
+	if got := braille(src, 1, 1, false); got[0] != "⡇" {
+		t.Errorf("braille = %q, want ⡇", got)
+	}
+	if got := braille(src, 1, 1, true); got[0] != "\033[38;2;255;255;255m⡇\033[0m" {
+		t.Errorf("braille z kolorem = %q", got)
+	}
+
+	if got := braille(image.NewNRGBA(image.Rect(0, 0, 2, 4)), 1, 1, false); got[0] != "\u2800" {
+		t.Errorf("przezroczysty obrazek = %q, want pusty znak Braille'a", got)
+	}
+}
@@ -0,0 +1,12 @@
+package graphics
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// iterm2 koduje obrazek jako obrazek w linii iTerm2 (OSC 1337) o rozmiarze cols×rows komórek.
+func (img *Image) iterm2(cols, rows int) string {
+	return fmt.Sprintf("\033]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=0:%s\a",
+		len(img.Data), cols, rows, base64.StdEncoding.EncodeToString(img.Data))
+}
@@ -0,0 +1,44 @@
+package graphics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"strings"
+)
+
+// kittyChunk to maksymalny rozmiar fragmentu danych base64 w jednej sekwencji protokołu Kitty.
+const kittyChunk = 4096
+
+// kitty koduje obrazek jako PNG (f=100) wyświetlany od razu (a=T) w obszarze cols×rows komórek,
+// bez przesuwania kursora (C=1).
+func (img *Image) kitty(cols, rows int) (string, error) {
+	data := img.Data
+	if img.Format != "png" {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img.Image); err != nil {
+			return "", fmt.Errorf("nie udało się zakodować PNG: %w", err)
+		}
+		data = buf.Bytes()
+	}
+	payload := base64.StdEncoding.EncodeToString(data)
+
+	var b strings.Builder
+	first := true
+	for len(payload) > 0 {
+		chunk := payload[:min(kittyChunk, len(payload))]
+		payload = payload[len(chunk):]
+		more := 0
+		if len(payload) > 0 {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(&b, "\033_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\033\\", cols, rows, more, chunk)
+			first = false
+		} else {
+			fmt.Fprintf(&b, "\033_Gm=%d;%s\033\\", more, chunk)
+		}
+	}
+	return b.String(), nil
+}
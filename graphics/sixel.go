@@ -0,0 +1,122 @@
+package graphics
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// sixel koduje obrazek przeskalowany do w×h pikseli jako Sixel, w stałej palecie 6×6×6.
+// Przezroczyste piksele nie są rysowane (P2=1).
+func (img *Image) sixel(w, h int) string {
+	pixels := Scale(img.Image, w, h)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\033P0;1;0q\"1;1;%d;%d", w, h)
+	for i := 0; i < 216; i++ {
+		r, g, bl := i/36, i/6%6, i%6
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r*20, g*20, bl*20)
+	}
+
+	index := make([]int, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			index[y*w+x] = paletteIndex(pixels.NRGBAAt(x, y))
+		}
+	}
+
+	row := make([]byte, w)
+	for top := 0; top < h; top += 6 {
+		used := map[int]bool{}
+		var order []int
+		for y := top; y < min(top+6, h); y++ {
+			for x := 0; x < w; x++ {
+				if c := index[y*w+x]; c >= 0 && !used[c] {
+					used[c] = true
+					order = append(order, c)
+				}
+			}
+		}
+
+		for n, c := range order {
+			for x := 0; x < w; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && top+dy < h; dy++ {
+					if index[(top+dy)*w+x] == c {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = '?' + bits
+			}
+			fmt.Fprintf(&b, "#%d", c)
+			writeRLE(&b, row)
+			if n < len(order)-1 {
+				b.WriteByte('$')
+			}
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\033\\")
+	return b.String()
+}
+
+// paletteIndex zwraca numer koloru w palecie 6×6×6 albo -1 dla piksela przezroczystego.
+func paletteIndex(c color.NRGBA) int {
+	if c.A < 128 {
+		return -1
+	}
+	level := func(v uint8) int { return (int(v)*5 + 127) / 255 }
+	return level(c.R)*36 + level(c.G)*6 + level(c.B)
+}
+
+// writeRLE zapisuje wiersz sixeli, skracając powtórzenia do !N<znak>.
+func writeRLE(b *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(b, "!%d%c", n, row[i])
+		} else {
+			b.Write(row[i:j])
+		}
+		i = j
+	}
+}
+
+// Scale zmniejsza (albo powiększa) obrazek do w×h pikseli, uśredniając piksele źródłowe
+// padające na każdy piksel docelowy. Wynik nie jest przemnożony przez kanał alfa.
+func Scale(src image.Image, w, h int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	sb := src.Bounds()
+	if sb.Empty() || w <= 0 || h <= 0 {
+		return dst
+	}
+	for y := 0; y < h; y++ {
+		y0 := sb.Min.Y + y*sb.Dy()/h
+		y1 := max(sb.Min.Y+(y+1)*sb.Dy()/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := sb.Min.X + x*sb.Dx()/w
+			x1 := max(sb.Min.X+(x+1)*sb.Dx()/w, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			c := color.NRGBA{A: uint8(a / n >> 8)}
+			if a > 0 {
+				c.R = uint8(r * 0xffff / a >> 8)
+				c.G = uint8(g * 0xffff / a >> 8)
+				c.B = uint8(b * 0xffff / a >> 8)
+			}
+			dst.SetNRGBA(x, y, c)
+		}
+	}
+	return dst
+}
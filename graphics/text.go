@@ -0,0 +1,106 @@
+package graphics
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// halfBlocks rysuje obrazek półblokami: każda komórka to dwa piksele w pionie, górny jako
+// kolor znaku ▀, dolny jako kolor tła. Wymaga kolorów 24-bitowych.
+func halfBlocks(src image.Image, cols, rows int) []string {
+	pixels := Scale(src, cols, rows*2)
+	lines := make([]string, rows)
+	for row := range lines {
+		var b strings.Builder
+		for x := 0; x < cols; x++ {
+			top, bottom := pixels.NRGBAAt(x, row*2), pixels.NRGBAAt(x, row*2+1)
+			switch {
+			case opaque(top) && opaque(bottom):
+				fmt.Fprintf(&b, "%s%s▀", fg(top), bg(bottom))
+			case opaque(top):
+				fmt.Fprintf(&b, "\033[49m%s▀", fg(top))
+			case opaque(bottom):
+				fmt.Fprintf(&b, "\033[49m%s▄", fg(bottom))
+			default:
+				b.WriteString("\033[49m ")
+			}
+		}
+		b.WriteString("\033[0m")
+		lines[row] = b.String()
+	}
+	return lines
+}
+
+// brailleBits to bity znaku Braille'a dla kropek w komórce 2×4, indeksowane [y][x].
+var brailleBits = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// braille rysuje obrazek znakami Braille'a: każda komórka to 2×4 piksele. Kropka jest
+// zapalona dla nieprzezroczystych pikseli jaśniejszych niż średnia. Z kolorami komórka
+// dostaje średni kolor swoich zapalonych kropek.
+func braille(src image.Image, cols, rows int, colored bool) []string {
+	pixels := Scale(src, cols*2, rows*4)
+
+	var sum, n int
+	for i := 0; i < len(pixels.Pix); i += 4 {
+		if c := (color.NRGBA{pixels.Pix[i], pixels.Pix[i+1], pixels.Pix[i+2], pixels.Pix[i+3]}); opaque(c) {
+			sum += luminance(c)
+			n++
+		}
+	}
+	threshold := 0
+	if n > 0 {
+		threshold = sum / n
+	}
+
+	lines := make([]string, rows)
+	for row := range lines {
+		var b strings.Builder
+		for col := 0; col < cols; col++ {
+			ch := rune(0x2800)
+			var r, g, bl, dots int
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					c := pixels.NRGBAAt(col*2+dx, row*4+dy)
+					if !opaque(c) || luminance(c) < threshold {
+						continue
+					}
+					ch |= brailleBits[dy][dx]
+					r, g, bl, dots = r+int(c.R), g+int(c.G), bl+int(c.B), dots+1
+				}
+			}
+			if colored && dots > 0 {
+				b.WriteString(fg(color.NRGBA{uint8(r / dots), uint8(g / dots), uint8(bl / dots), 255}))
+			}
+			b.WriteRune(ch)
+		}
+		if colored {
+			b.WriteString("\033[0m")
+		}
+		lines[row] = b.String()
+	}
+	return lines
+}
+
+func opaque(c color.NRGBA) bool {
+	return c.A >= 128
+}
+
+// luminance zwraca jasność w skali 0–255 (wagi BT.601).
+func luminance(c color.NRGBA) int {
+	return (299*int(c.R) + 587*int(c.G) + 114*int(c.B)) / 1000
+}
+
+func fg(c color.NRGBA) string {
+	return fmt.Sprintf("\033[38;2;%d;%d;%dm", c.R, c.G, c.B)
+}
+
+func bg(c color.NRGBA) string {
+	return fmt.Sprintf("\033[48;2;%d;%d;%dm", c.R, c.G, c.B)
+}
@@ -1,41 +1,60 @@
 package hardware
 
 import (
+	"asf/modules"
+	"asf/system"
 	"fmt"
-	"io/ioutil"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 )
 
-func GetBatteryInfo() string {
-	if runtime.GOOS == "linux" {
-		batteryPath := "/sys/class/power_supply/"
-		files, err := ioutil.ReadDir(batteryPath)
-		if err != nil {
-			return "N/A"
+type BatteryInfo struct {
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+	Status   string `json:"status"`
+}
+
+func GetBatteryInfo(sys *system.System) ([]BatteryInfo, error) {
+	if runtime.GOOS != "linux" {
+		return nil, modules.ErrUnavailable
+	}
+
+	batteryPath := "/sys/class/power_supply/"
+	files, err := sys.ReadDir(batteryPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", modules.ErrUnavailable, err)
+	}
+
+	var batteries []BatteryInfo
+	for _, file := range files {
+		if !strings.HasPrefix(file.Name(), "BAT") {
+			continue
 		}
 
-		for _, file := range files {
-			if strings.HasPrefix(file.Name(), "BAT") {
-				capacityFile := filepath.Join(batteryPath, file.Name(), "capacity")
-				statusFile := filepath.Join(batteryPath, file.Name(), "status")
-
-				capacity, err := ioutil.ReadFile(capacityFile)
-				if err != nil {
-					continue
-				}
-				status, err := ioutil.ReadFile(statusFile)
-				if err != nil {
-					continue
-				}
-
-				capStr := strings.TrimSpace(string(capacity))
-				statStr := strings.TrimSpace(string(status))
-
-				return fmt.Sprintf("%s%% (%s)", capStr, statStr)
-			}
+		capacity, err := sys.ReadFile(filepath.Join(batteryPath, file.Name(), "capacity"))
+		if err != nil {
+			continue
 		}
+		status, err := sys.ReadFile(filepath.Join(batteryPath, file.Name(), "status"))
+		if err != nil {
+			continue
+		}
+		capInt, err := strconv.Atoi(strings.TrimSpace(string(capacity)))
+		if err != nil {
+			continue
+		}
+
+		batteries = append(batteries, BatteryInfo{
+			Name:     file.Name(),
+			Capacity: capInt,
+			Status:   strings.TrimSpace(string(status)),
+		})
+	}
+
+	if len(batteries) == 0 {
+		return nil, modules.ErrUnavailable
 	}
-	return "N/A"
+	return batteries, nil
 }
@@ -0,0 +1,45 @@
+package hardware
+
+import (
+	"asf/modules"
+	"asf/system"
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGetBatteryInfoMultiple(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sys/class/power_supply/AC/online":       {Data: []byte("1\n")},
+		"sys/class/power_supply/BAT0/capacity":   {Data: []byte("87\n")},
+		"sys/class/power_supply/BAT0/status":     {Data: []byte("Charging\n")},
+		"sys/class/power_supply/BAT1/capacity":   {Data: []byte("100\n")},
+		"sys/class/power_supply/BAT1/status":     {Data: []byte("Full\n")},
+		"sys/class/power_supply/BAT2/status":     {Data: []byte("Unknown\n")},
+		"sys/class/power_supply/hidpp_battery_0": {Mode: fs.ModeDir | 0755},
+	}
+
+	got, err := GetBatteryInfo(system.FromFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []BatteryInfo{
+		{Name: "BAT0", Capacity: 87, Status: "Charging"},
+		{Name: "BAT1", Capacity: 100, Status: "Full"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetBatteryInfo() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetBatteryInfoDesktop(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sys/class/power_supply/AC/online": {Data: []byte("1\n")},
+	}
+
+	if _, err := GetBatteryInfo(system.FromFS(fsys)); !errors.Is(err, modules.ErrUnavailable) {
+		t.Errorf("err = %v, want modules.ErrUnavailable", err)
+	}
+}
@@ -1,107 +1,80 @@
 package hardware
 
 import (
+	"asf/system"
 	"bufio"
 	"bytes"
 	"fmt"
-	"io/ioutil"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
-	"sync"
 )
 
-var (
-	cachedCPUInfo string
-	cpuInfoOnce   sync.Once
-)
+var reCPUFreqSuffix = regexp.MustCompile(`@\s*[\d.]+GHz`)
 
-func GetCPUInfo() string {
-	cpuInfoOnce.Do(func() {
-		cpuName := "unknown"
-		cpuCores := 0
-		cpuThreads := 0
-		maxFreqToReport := 0.0
+type CPUInfo struct {
+	Model     string `json:"model"`
+	Cores     int    `json:"cores"`
+	Threads   int    `json:"threads"`
+	MaxFreqHz uint64 `json:"max_freq_hz"`
+}
 
-		if data, err := ioutil.ReadFile("/proc/cpuinfo"); err == nil {
-			scanner := bufio.NewScanner(bytes.NewReader(data))
-			for scanner.Scan() {
-				line := scanner.Text()
-				if strings.HasPrefix(line, "model name") {
-					parts := strings.Split(line, ":")
-					if len(parts) > 1 {
-						cpuName = strings.TrimSpace(parts[1])
-						cpuName = regexp.MustCompile(`@\s*[\d.]+GHz`).ReplaceAllString(cpuName, "")
-						cpuName = strings.TrimSpace(cpuName)
-					}
-				} else if strings.HasPrefix(line, "cpu cores") {
-					parts := strings.Split(line, ":")
-					if len(parts) > 1 {
-						if cores, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
-							cpuCores = cores
-						}
-					}
-				} else if strings.HasPrefix(line, "siblings") {
-					parts := strings.Split(line, ":")
-					if len(parts) > 1 {
-						if threads, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
-							cpuThreads = threads
-						}
-					}
-				}
-			}
-		}
+func GetCPUInfo(sys *system.System) (CPUInfo, error) {
+	info := CPUInfo{}
 
-		if cpuThreads == 0 {
-			cpuThreads = cpuCores
-		}
-		if cpuThreads == 0 {
-			cpuThreads = 1
-		}
+	data, err := sys.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return info, fmt.Errorf("nie udało się odczytać /proc/cpuinfo: %w", err)
+	}
 
-		for i := 0; i < cpuThreads; i++ {
-			freqPath := filepath.Join("/sys/devices/system/cpu", fmt.Sprintf("cpu%d", i), "cpufreq", "cpuinfo_max_freq")
-			if data, err := ioutil.ReadFile(freqPath); err == nil {
-				if freqKHz, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64); err == nil {
-					freqGHz := freqKHz / 1_000_000
-					if freqGHz > maxFreqToReport {
-						maxFreqToReport = freqGHz
-					}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "model name") {
+			parts := strings.Split(line, ":")
+			if len(parts) > 1 {
+				info.Model = strings.TrimSpace(reCPUFreqSuffix.ReplaceAllString(strings.TrimSpace(parts[1]), ""))
+			}
+		} else if strings.HasPrefix(line, "cpu cores") {
+			parts := strings.Split(line, ":")
+			if len(parts) > 1 {
+				if cores, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
+					info.Cores = cores
 				}
-			} else {
-				freqPath = filepath.Join("/sys/devices/system/cpu", fmt.Sprintf("cpu%d", i), "cpufreq", "scaling_max_freq")
-				if data, err := ioutil.ReadFile(freqPath); err == nil {
-					if freqKHz, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64); err == nil {
-						freqGHz := freqKHz / 1_000_000
-						if freqGHz > maxFreqToReport {
-							maxFreqToReport = freqGHz
-						}
-					}
+			}
+		} else if strings.HasPrefix(line, "siblings") {
+			parts := strings.Split(line, ":")
+			if len(parts) > 1 {
+				if threads, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
+					info.Threads = threads
 				}
 			}
 		}
+	}
 
-		var cpuDetails []string
-		if cpuName != "unknown" && cpuName != "" {
-			cpuDetails = append(cpuDetails, cpuName)
-		} else {
-			cpuDetails = append(cpuDetails, "Nieznany CPU")
-		}
+	if info.Threads == 0 {
+		info.Threads = info.Cores
+	}
+	if info.Threads == 0 {
+		info.Threads = 1
+	}
 
-		if cpuCores > 0 {
-			coreInfo := fmt.Sprintf("%dC", cpuCores)
-			if cpuThreads > 0 && cpuThreads != cpuCores {
-				coreInfo += fmt.Sprintf("/%dT", cpuThreads)
+	for i := 0; i < info.Threads; i++ {
+		for _, name := range []string{"cpuinfo_max_freq", "scaling_max_freq"} {
+			freqPath := filepath.Join("/sys/devices/system/cpu", fmt.Sprintf("cpu%d", i), "cpufreq", name)
+			data, err := sys.ReadFile(freqPath)
+			if err != nil {
+				continue
 			}
-			cpuDetails = append(cpuDetails, coreInfo)
-		}
-
-		if maxFreqToReport > 0 {
-			cpuDetails = append(cpuDetails, fmt.Sprintf("%.2fGHz", maxFreqToReport))
+			if freqKHz, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64); err == nil {
+				if freqHz := freqKHz * 1000; freqHz > info.MaxFreqHz {
+					info.MaxFreqHz = freqHz
+				}
+			}
+			break
 		}
+	}
 
-		cachedCPUInfo = strings.Join(cpuDetails, ", ")
-	})
-	return cachedCPUInfo
+	return info, nil
 }
@@ -0,0 +1,71 @@
+package hardware
+
+import (
+	"asf/system"
+	"strconv"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGetCPUInfoHybrid(t *testing.T) {
+	// Alder Lake: 6 rdzeni P z HT + 8 rdzeni E, różne częstotliwości maksymalne.
+	cpuinfo := ""
+	for i := 0; i < 20; i++ {
+		cpuinfo += "processor\t: " + strconv.Itoa(i) + "\n" +
+			"model name\t: 12th Gen Intel(R) Core(TM) i7-12700H\n" +
+			"siblings\t: 20\n" +
+			"cpu cores\t: 14\n\n"
+	}
+	fsys := fstest.MapFS{
+		"proc/cpuinfo": {Data: []byte(cpuinfo)},
+		"sys/devices/system/cpu/cpu0/cpufreq/cpuinfo_max_freq":  {Data: []byte("4700000\n")},
+		"sys/devices/system/cpu/cpu12/cpufreq/cpuinfo_max_freq": {Data: []byte("3500000\n")},
+		"sys/devices/system/cpu/cpu19/cpufreq/scaling_max_freq": {Data: []byte("3500000\n")},
+	}
+
+	info, err := GetCPUInfo(system.FromFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := CPUInfo{Model: "12th Gen Intel(R) Core(TM) i7-12700H", Cores: 14, Threads: 20, MaxFreqHz: 4_700_000_000}
+	if info != want {
+		t.Errorf("GetCPUInfo() = %+v, want %+v", info, want)
+	}
+}
+
+func TestGetCPUInfoStripsFrequencySuffix(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/cpuinfo": {Data: []byte("model name\t: Intel(R) Core(TM) i5-3570 CPU @ 3.40GHz\ncpu cores\t: 4\nsiblings\t: 4\n")},
+	}
+
+	info, err := GetCPUInfo(system.FromFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Model != "Intel(R) Core(TM) i5-3570 CPU" {
+		t.Errorf("Model = %q", info.Model)
+	}
+	if info.Threads != 4 || info.MaxFreqHz != 0 {
+		t.Errorf("Threads = %d, MaxFreqHz = %d", info.Threads, info.MaxFreqHz)
+	}
+}
+
+func TestGetCPUInfoARMWithoutModelName(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/cpuinfo": {Data: []byte("processor\t: 0\nBogoMIPS\t: 48.00\nCPU implementer\t: 0x41\n")},
+	}
+
+	info, err := GetCPUInfo(system.FromFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Model != "" || info.Threads != 1 {
+		t.Errorf("GetCPUInfo() = %+v", info)
+	}
+}
+
+func TestGetCPUInfoMissing(t *testing.T) {
+	if _, err := GetCPUInfo(system.FromFS(fstest.MapFS{})); err == nil {
+		t.Error("expected error without /proc/cpuinfo")
+	}
+}
@@ -0,0 +1,166 @@
+package hardware
+
+import (
+	"asf/modules"
+	"asf/system"
+	"bufio"
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// DiskOptions to ustawienia modułu disk z pola "disk" konfiguracji.
+type DiskOptions struct {
+	// Mountpoints to punkty montowania do pokazania, w tej kolejności. Pusta lista oznacza
+	// wszystkie prawdziwe systemy plików (bez tmpfs, overlay, squashfs itp.).
+	Mountpoints []string `json:"mountpoints,omitempty"`
+	// ShowFSType dopisuje typ systemu plików (ext4, btrfs...) do punktu montowania.
+	ShowFSType bool `json:"show_fs_type,omitempty"`
+}
+
+type DiskInfo struct {
+	Mountpoint string `json:"mountpoint"`
+	Device     string `json:"device"`
+	// FSType jest pusty, gdy DiskOptions.ShowFSType jest wyłączone.
+	FSType string `json:"fs_type,omitempty"`
+	Usage
+}
+
+// mount to jeden wpis z /proc/self/mounts.
+type mount struct {
+	device, mountpoint, fsType string
+}
+
+// pseudoFS to systemy plików, które nie są dyskami: interfejsy jądra (proc, sysfs, cgroup...),
+// systemy żyjące w pamięci, obrazy tylko do odczytu (snapy, AppImage) albo warstwy kontenerów.
+var pseudoFS = map[string]bool{
+	"autofs":          true,
+	"binfmt_misc":     true,
+	"bpf":             true,
+	"cgroup":          true,
+	"cgroup2":         true,
+	"configfs":        true,
+	"debugfs":         true,
+	"devpts":          true,
+	"devtmpfs":        true,
+	"efivarfs":        true,
+	"erofs":           true,
+	"fuse.gvfsd-fuse": true,
+	"fuse.portal":     true,
+	"fusectl":         true,
+	"hugetlbfs":       true,
+	"mqueue":          true,
+	"nsfs":            true,
+	"overlay":         true,
+	"proc":            true,
+	"pstore":          true,
+	"ramfs":           true,
+	"rpc_pipefs":      true,
+	"securityfs":      true,
+	"squashfs":        true,
+	"sysfs":           true,
+	"tmpfs":           true,
+	"tracefs":         true,
+}
+
+func GetDiskInfo(sys *system.System, opts DiskOptions) ([]DiskInfo, error) {
+	if runtime.GOOS != "linux" {
+		return nil, modules.ErrUnavailable
+	}
+
+	data, err := sys.ReadFile("/proc/self/mounts")
+	if err != nil {
+		return nil, fmt.Errorf("nie udało się odczytać /proc/self/mounts: %w", err)
+	}
+	mounts := selectMounts(parseMounts(data), opts.Mountpoints)
+
+	var disks []DiskInfo
+	for _, m := range mounts {
+		space, err := sys.DiskSpace(m.mountpoint)
+		if err != nil || space.Total == 0 {
+			continue
+		}
+		// Jak w df: zajęte to wszystko poza wolnymi blokami, a pojemność to zajęte plus miejsce
+		// dostępne dla użytkownika, więc bloki zarezerwowane dla roota nie zaniżają procentu.
+		used := space.Total - space.Free
+		disk := DiskInfo{Mountpoint: m.mountpoint, Device: m.device, Usage: NewUsage(used, used+space.Avail)}
+		if opts.ShowFSType {
+			disk.FSType = m.fsType
+		}
+		disks = append(disks, disk)
+	}
+
+	if len(disks) == 0 {
+		return nil, modules.ErrUnavailable
+	}
+	return disks, nil
+}
+
+func parseMounts(data []byte) []mount {
+	var mounts []mount
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		mounts = append(mounts, mount{
+			device:     unescapeMount(fields[0]),
+			mountpoint: unescapeMount(fields[1]),
+			fsType:     fields[2],
+		})
+	}
+	return mounts
+}
+
+// unescapeMount zamienia sekwencje ósemkowe z /proc/self/mounts (np. \040 dla spacji) na znaki.
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// selectMounts zwraca wpisy dla wybranych punktów montowania albo, gdy lista jest pusta,
+// prawdziwe systemy plików: bez pseudo systemów plików i bez powtórzeń tego samego urządzenia
+// (podwoluminy btrfs, bind mounty). O wyborze decyduje tylko typ, bo urządzenie nie musi być
+// ścieżką: zbiory danych ZFS nazywają się np. rpool/ROOT/ubuntu.
+func selectMounts(mounts []mount, mountpoints []string) []mount {
+	if len(mountpoints) > 0 {
+		var selected []mount
+		for _, mp := range mountpoints {
+			// Późniejszy wpis przykrywa wcześniejszy w tym samym miejscu, więc szukamy od końca.
+			for i := len(mounts) - 1; i >= 0; i-- {
+				if mounts[i].mountpoint == mp {
+					selected = append(selected, mounts[i])
+					break
+				}
+			}
+		}
+		return selected
+	}
+
+	var selected []mount
+	seen := map[string]bool{}
+	for _, m := range mounts {
+		if pseudoFS[m.fsType] || seen[m.device] {
+			continue
+		}
+		seen[m.device] = true
+		selected = append(selected, m)
+	}
+	return selected
+}
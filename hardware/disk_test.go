@@ -0,0 +1,100 @@
+package hardware
+
+import (
+	"asf/modules"
+	"asf/system"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+const testMounts = `proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0
+devtmpfs /dev devtmpfs rw,nosuid,size=8000000k 0 0
+/dev/nvme0n1p2 / btrfs rw,relatime,subvol=/@ 0 0
+tmpfs /tmp tmpfs rw,nosuid,nodev 0 0
+/dev/nvme0n1p2 /home btrfs rw,relatime,subvol=/@home 0 0
+/dev/nvme0n1p1 /boot vfat rw,relatime 0 0
+/dev/loop3 /snap/core22/1380 squashfs ro,nodev,relatime 0 0
+overlay /var/lib/docker/overlay2/abc/merged overlay rw,relatime 0 0
+/dev/sda1 /mnt/Dysk\040USB ext4 rw,relatime 0 0
+rpool/data/dom /tank zfs rw,xattr,noacl 0 0
+cgroup2 /sys/fs/cgroup cgroup2 rw,nosuid,nodev,noexec,relatime 0 0
+`
+
+// withStatfs zwraca system z /proc/self/mounts z testMounts i pojemnościami z mapy.
+func withStatfs(space map[string]system.DiskSpace) *system.System {
+	sys := system.FromFS(fstest.MapFS{"proc/self/mounts": {Data: []byte(testMounts)}})
+	return sys.WithStatfs(func(path string) (system.DiskSpace, error) {
+		s, ok := space[path]
+		if !ok {
+			return system.DiskSpace{}, errors.New("brak")
+		}
+		return s, nil
+	})
+}
+
+// space zwraca pojemność bez bloków zarezerwowanych dla roota.
+func space(used, total uint64) system.DiskSpace {
+	return system.DiskSpace{Total: total, Free: total - used, Avail: total - used}
+}
+
+func TestGetDiskInfo(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+	sys := withStatfs(map[string]system.DiskSpace{
+		"/":     space(100*gib, 400*gib),
+		"/home": space(100*gib, 400*gib),
+		"/boot": space(gib/4, gib),
+		"/tmp":  space(gib, 8*gib),
+		// ext4 rezerwuje 5% bloków dla roota: df liczy procent względem zajętego i dostępnego miejsca.
+		"/mnt/Dysk USB":     {Total: 20 * gib, Free: 10 * gib, Avail: 9 * gib},
+		"/snap/core22/1380": space(gib, gib),
+		"/tank":             space(300*gib, 900*gib),
+		"/sys/fs/cgroup":    {},
+	})
+
+	tests := []struct {
+		name string
+		opts DiskOptions
+		want []DiskInfo
+	}{
+		{
+			name: "default",
+			want: []DiskInfo{
+				{Mountpoint: "/", Device: "/dev/nvme0n1p2", Usage: NewUsage(100*gib, 400*gib)},
+				{Mountpoint: "/boot", Device: "/dev/nvme0n1p1", Usage: NewUsage(gib/4, gib)},
+				{Mountpoint: "/mnt/Dysk USB", Device: "/dev/sda1", Usage: NewUsage(10*gib, 19*gib)},
+				{Mountpoint: "/tank", Device: "rpool/data/dom", Usage: NewUsage(300*gib, 900*gib)},
+			},
+		},
+		{
+			name: "mountpoints with fs type",
+			opts: DiskOptions{Mountpoints: []string{"/home", "/tmp", "/nie/ma"}, ShowFSType: true},
+			want: []DiskInfo{
+				{Mountpoint: "/home", Device: "/dev/nvme0n1p2", FSType: "btrfs", Usage: NewUsage(100*gib, 400*gib)},
+				{Mountpoint: "/tmp", Device: "tmpfs", FSType: "tmpfs", Usage: NewUsage(gib, 8*gib)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDiskInfo(sys, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDiskInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDiskInfoNoMatches(t *testing.T) {
+	sys := withStatfs(nil)
+
+	if _, err := GetDiskInfo(sys, DiskOptions{Mountpoints: []string{"/nie/ma"}}); !errors.Is(err, modules.ErrUnavailable) {
+		t.Errorf("err = %v, want modules.ErrUnavailable", err)
+	}
+}
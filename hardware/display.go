@@ -0,0 +1,163 @@
+package hardware
+
+import (
+	"asf/modules"
+	"asf/system"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+var reDRMConnector = regexp.MustCompile(`^card\d+-(.+)$`)
+
+type DisplayInfo struct {
+	// Connector to nazwa złącza z DRM, np. "eDP-1", "DP-2", "HDMI-A-1".
+	Connector    string `json:"connector"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	Model        string `json:"model,omitempty"`
+	// Width i Height to natywna rozdzielczość z EDID (albo pierwszy tryb z listy modes).
+	Width     int     `json:"width"`
+	Height    int     `json:"height"`
+	RefreshHz float64 `json:"refresh_hz,omitempty"`
+}
+
+// edidVendors tłumaczy identyfikatory PNP producentów z EDID na nazwy handlowe.
+var edidVendors = map[string]string{
+	"ACR": "Acer",
+	"AOC": "AOC",
+	"APP": "Apple",
+	"AUO": "AU Optronics",
+	"AUS": "ASUS",
+	"BNQ": "BenQ",
+	"BOE": "BOE",
+	"CMN": "Innolux",
+	"DEL": "Dell",
+	"ENC": "EIZO",
+	"GBT": "Gigabyte",
+	"GSM": "LG",
+	"HPN": "HP",
+	"HWP": "HP",
+	"IVM": "Iiyama",
+	"LEN": "Lenovo",
+	"LGD": "LG Display",
+	"MSI": "MSI",
+	"PHL": "Philips",
+	"SAM": "Samsung",
+	"SDC": "Samsung Display",
+	"SHP": "Sharp",
+	"SNY": "Sony",
+	"VSC": "ViewSonic",
+}
+
+// GetDisplayInfo zwraca podłączone monitory na podstawie złączy DRM w sysfs, więc działa tak samo
+// pod X11, Waylandem i na konsoli, bez xrandr.
+func GetDisplayInfo(sys *system.System) ([]DisplayInfo, error) {
+	if runtime.GOOS != "linux" {
+		return nil, modules.ErrUnavailable
+	}
+
+	drmPath := "/sys/class/drm"
+	entries, err := sys.ReadDir(drmPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", modules.ErrUnavailable, err)
+	}
+
+	var displays []DisplayInfo
+	for _, entry := range entries {
+		match := reDRMConnector.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		dir := path.Join(drmPath, entry.Name())
+		if readTrimmed(sys, path.Join(dir, "status")) != "connected" {
+			continue
+		}
+
+		display := DisplayInfo{Connector: match[1]}
+		if edid, err := sys.ReadFile(path.Join(dir, "edid")); err == nil {
+			if info, ok := parseEDID(edid); ok {
+				info.Connector = display.Connector
+				display = info
+			}
+		}
+		if display.Width == 0 {
+			// Bez EDID (np. wirtualne GPU) pierwszy tryb z listy jest trybem preferowanym.
+			mode, _, _ := strings.Cut(readTrimmed(sys, path.Join(dir, "modes")), "\n")
+			fmt.Sscanf(mode, "%dx%d", &display.Width, &display.Height)
+		}
+		displays = append(displays, display)
+	}
+
+	if len(displays) == 0 {
+		return nil, modules.ErrUnavailable
+	}
+	return displays, nil
+}
+
+var edidHeader = []byte{0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00}
+
+// parseEDID odczytuje z bloku bazowego EDID producenta, nazwę monitora oraz natywny tryb,
+// czyli pierwszy szczegółowy opis taktowania (Detailed Timing Descriptor).
+func parseEDID(edid []byte) (DisplayInfo, bool) {
+	var info DisplayInfo
+	if len(edid) < 128 || !bytes.Equal(edid[:8], edidHeader) {
+		return info, false
+	}
+
+	// Trzy litery po 5 bitów, 'A' = 1.
+	id := binary.BigEndian.Uint16(edid[8:10])
+	vendor := string([]byte{
+		byte(id>>10&0x1f) + 'A' - 1,
+		byte(id>>5&0x1f) + 'A' - 1,
+		byte(id&0x1f) + 'A' - 1,
+	})
+	info.Manufacturer = vendor
+	if name, ok := edidVendors[vendor]; ok {
+		info.Manufacturer = name
+	}
+
+	for offset := 54; offset+18 <= 126; offset += 18 {
+		d := edid[offset : offset+18]
+		if clock := binary.LittleEndian.Uint16(d[0:2]); clock != 0 {
+			if info.Width != 0 {
+				continue
+			}
+			hActive := int(d[2]) | int(d[4]&0xf0)<<4
+			hBlank := int(d[3]) | int(d[4]&0x0f)<<8
+			vActive := int(d[5]) | int(d[7]&0xf0)<<4
+			vBlank := int(d[6]) | int(d[7]&0x0f)<<8
+			info.Width, info.Height = hActive, vActive
+			if total := (hActive + hBlank) * (vActive + vBlank); total > 0 {
+				refresh := float64(clock) * 10_000 / float64(total)
+				info.RefreshHz = math.Round(refresh*100) / 100
+			}
+			continue
+		}
+		// Deskryptor 0xFC to nazwa monitora, zakończona znakiem nowej linii i dopełniona spacjami.
+		if d[3] == 0xfc {
+			name, _, _ := strings.Cut(string(d[5:18]), "\n")
+			info.Model = strings.TrimSpace(name)
+		}
+	}
+	// Nazwa często zaczyna się od producenta ("DELL U2720Q"), który i tak stoi przed modelem.
+	info.Model = trimManufacturer(info.Model, info.Manufacturer, vendor)
+	return info, true
+}
+
+// trimManufacturer usuwa z początku modelu nazwę producenta albo jego identyfikator PNP,
+// bez względu na wielkość liter.
+func trimManufacturer(model string, prefixes ...string) string {
+	for _, prefix := range prefixes {
+		if len(model) >= len(prefix) && strings.EqualFold(model[:len(prefix)], prefix) {
+			if rest := model[len(prefix):]; rest == "" || rest[0] == ' ' {
+				return strings.TrimSpace(rest)
+			}
+		}
+	}
+	return model
+}
@@ -1,11 +1,12 @@
 package hardware
 
 import (
+	"asf/modules"
+	"asf/system"
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"os/exec"
 	"regexp"
 	"runtime"
 	"strings"
@@ -15,13 +16,17 @@ var (
 	reDeviceNameVulkan = regexp.MustCompile(`deviceName\s*=\s*(.+?)(?:\s+\(.+\))?\n`)
 	reIntelLspci       = regexp.MustCompile(`Intel Corporation\s+[^\]]*?\[((?:UHD|HD|Iris [PX]e|Iris Plus|Xe) Graphics[^\]]*?)\]`)
 	reNaviLspci        = regexp.MustCompile(`Navi \d+ \[((?:Radeon RX|GeForce RTX|Iris Xe Graphics|UHD Graphics)[^\]]*?)\]`)
+	reLastBracket      = regexp.MustCompile(`\[([^\]]+)\]$`)
+	reLspciIDs         = regexp.MustCompile(` \[[0-9a-f]{4}(?::[0-9a-f]{4})?\]`)
+	reRevision         = regexp.MustCompile(`\(rev [0-9a-fA-F]+\)`)
+	reGenericDevice    = regexp.MustCompile(`^Device(?: [0-9a-f]{4})?$`)
 )
 
 type GPUDetails struct {
-	Vendor      string
-	Model       string
-	PCI_ID      string
-	SubsystemID string
+	Vendor      string `json:"vendor,omitempty"`
+	Model       string `json:"model"`
+	PCI_ID      string `json:"pci_id,omitempty"`
+	SubsystemID string `json:"subsystem_id,omitempty"`
 }
 
 func mapPciVendorIDToName(vID string) string {
@@ -39,8 +44,8 @@ func mapPciVendorIDToName(vID string) string {
 	}
 }
 
-func getGPUInfoFromVulkaninfo() (string, error) {
-	out, err := exec.Command("vulkaninfo").Output()
+func getGPUInfoFromVulkaninfo(ctx context.Context, sys *system.System) (string, error) {
+	out, err := sys.Output(ctx, "vulkaninfo")
 	if err != nil {
 		return "", fmt.Errorf("nie można uruchomić vulkaninfo: %w", err)
 	}
@@ -57,15 +62,15 @@ func getGPUInfoFromVulkaninfo() (string, error) {
 	return "", fmt.Errorf("nie znaleziono nazwy urządzenia w wyjściu vulkaninfo")
 }
 
-func getGPUDetailsFromSysfs(cardIndex int) (GPUDetails, error) {
+func getGPUDetailsFromSysfs(sys *system.System, cardIndex int) (GPUDetails, error) {
 	details := GPUDetails{}
 	vendorPath := fmt.Sprintf("/sys/class/drm/card%d/device/vendor", cardIndex)
 	devicePath := fmt.Sprintf("/sys/class/drm/card%d/device/device", cardIndex)
 	subsystemVendorPath := fmt.Sprintf("/sys/class/drm/card%d/device/subsystem_vendor", cardIndex)
 	subsystemDevicePath := fmt.Sprintf("/sys/class/drm/card%d/device/subsystem_device", cardIndex)
 
-	vIDBytes, errV := ioutil.ReadFile(vendorPath)
-	dIDBytes, errD := ioutil.ReadFile(devicePath)
+	vIDBytes, errV := sys.ReadFile(vendorPath)
+	dIDBytes, errD := sys.ReadFile(devicePath)
 	if errV != nil || errD != nil {
 		return details, fmt.Errorf("nie można odczytać ID producenta/urządzenia z sysfs dla karty %d", cardIndex)
 	}
@@ -76,9 +81,9 @@ func getGPUDetailsFromSysfs(cardIndex int) (GPUDetails, error) {
 	details.Vendor = mapPciVendorIDToName(vID)
 	details.PCI_ID = fmt.Sprintf("%s:%s", vID, dID)
 
-	if subVBytes, err := ioutil.ReadFile(subsystemVendorPath); err == nil {
+	if subVBytes, err := sys.ReadFile(subsystemVendorPath); err == nil {
 		subV := strings.TrimSpace(strings.TrimPrefix(string(subVBytes), "0x"))
-		if subDBytes, err := ioutil.ReadFile(subsystemDevicePath); err == nil {
+		if subDBytes, err := sys.ReadFile(subsystemDevicePath); err == nil {
 			subD := strings.TrimSpace(strings.TrimPrefix(string(subDBytes), "0x"))
 			details.SubsystemID = fmt.Sprintf("%s:%s", subV, subD)
 		}
@@ -86,7 +91,40 @@ func getGPUDetailsFromSysfs(cardIndex int) (GPUDetails, error) {
 	return details, nil
 }
 
-func getGPUModelFromLspci(pciID string, vendorName string) string {
+func gpuModelFromLspciLine(line string, vendorName string) string {
+	line = reLspciIDs.ReplaceAllString(line, "")
+
+	if vendorName == "" || vendorName == "Intel" {
+		if match := reIntelLspci.FindStringSubmatch(line); len(match) > 1 {
+			return strings.TrimSpace(match[1])
+		}
+	}
+
+	if match := reNaviLspci.FindStringSubmatch(line); len(match) > 1 {
+		return strings.TrimSpace(match[1])
+	}
+
+	_, gpu, ok := strings.Cut(line, ": ")
+	if !ok {
+		return ""
+	}
+	for _, vendor := range []string{"Advanced Micro Devices, Inc.", "[AMD/ATI]", "[AMD]", "NVIDIA Corporation", "Intel Corporation"} {
+		gpu = strings.Replace(gpu, vendor, "", -1)
+	}
+	gpu = strings.TrimSpace(reRevision.ReplaceAllString(gpu, ""))
+
+	// Nazwa handlowa, jeśli jest, stoi w ostatnim nawiasie: "GA104 [GeForce RTX 3070]".
+	if match := reLastBracket.FindStringSubmatch(gpu); len(match) > 1 {
+		return strings.TrimSpace(match[1])
+	}
+	// Bez wpisu w pci.ids lspci pokazuje samo "Device" (albo "Device a7a0"), co nie jest nazwą modelu.
+	if reGenericDevice.MatchString(gpu) {
+		return ""
+	}
+	return gpu
+}
+
+func getGPUModelFromLspci(ctx context.Context, sys *system.System, pciID string, vendorName string) string {
 	if vendorName == "VMware" {
 		if pciID == "1af4:1050" {
 			return "VMware SVGA II Adapter"
@@ -94,87 +132,58 @@ func getGPUModelFromLspci(pciID string, vendorName string) string {
 		return "VMware Virtual Adapter"
 	}
 
-	if outLspci, err := exec.Command("lspci").Output(); err == nil {
+	if outLspci, err := sys.Output(ctx, "lspci", "-nn"); err == nil {
 		scannerLspci := bufio.NewScanner(bytes.NewReader(outLspci))
 		for scannerLspci.Scan() {
 			line := scannerLspci.Text()
-			if strings.Contains(line, pciID) || (strings.Contains(line, "VGA") || strings.Contains(line, "3D")) {
-
-				if vendorName == "Intel" {
-					if match := reIntelLspci.FindStringSubmatch(line); len(match) > 1 {
-						return strings.TrimSpace(match[1])
-					}
-				}
-
-				if match := reNaviLspci.FindStringSubmatch(line); len(match) > 1 {
-					return strings.TrimSpace(match[1])
-				}
-
-				parts := strings.SplitN(line, ": ", 3)
-				if len(parts) > 2 {
-					gpu := strings.TrimSpace(parts[2])
-					gpu = strings.Replace(gpu, "Advanced Micro Devices, Inc.", "", -1)
-					gpu = strings.Replace(gpu, "NVIDIA Corporation", "", -1)
-					gpu = strings.Replace(gpu, "Intel Corporation", "", -1)
-					gpu = regexp.MustCompile(`\[.*?\]`).ReplaceAllString(gpu, "")
-					gpu = regexp.MustCompile(`\(rev [0-9a-fA-F]+\)`).ReplaceAllString(gpu, "")
-					return strings.TrimSpace(gpu)
-				}
+			if strings.Contains(line, "["+pciID+"]") {
+				return gpuModelFromLspciLine(line, vendorName)
 			}
 		}
 	}
 	return ""
 }
 
-func GetGPUInfo() string {
+// GetGPUInfo zwraca listę wykrytych kart graficznych. Najpierw próbuje vulkaninfo,
+// potem sysfs (+ lspci dla nazwy modelu), a na końcu samego lspci.
+func GetGPUInfo(ctx context.Context, sys *system.System) ([]GPUDetails, error) {
 	if runtime.GOOS != "linux" {
-		return "N/A"
+		return nil, modules.ErrUnavailable
 	}
 
-	if gpuInfo, err := getGPUInfoFromVulkaninfo(); err == nil && gpuInfo != "" {
-		return gpuInfo
+	if gpuInfo, err := getGPUInfoFromVulkaninfo(ctx, sys); err == nil && gpuInfo != "" {
+		return []GPUDetails{{Model: gpuInfo}}, nil
 	}
 
+	var gpus []GPUDetails
 	for i := 0; i < 4; i++ {
-		details, err := getGPUDetailsFromSysfs(i)
-		if err == nil {
-			model := getGPUModelFromLspci(details.PCI_ID, details.Vendor)
-			if model == "" {
-				if details.SubsystemID != "" {
-					model = fmt.Sprintf("%s (ID: %s SubID: %s)", details.Vendor, details.PCI_ID, details.SubsystemID)
-				} else {
-					model = fmt.Sprintf("%s (ID: %s)", details.Vendor, details.PCI_ID)
-				}
-			}
-			return model
+		details, err := getGPUDetailsFromSysfs(sys, i)
+		if err != nil {
+			continue
 		}
+		details.Model = getGPUModelFromLspci(ctx, sys, details.PCI_ID, details.Vendor)
+		gpus = append(gpus, details)
+	}
+	if len(gpus) > 0 {
+		return gpus, nil
 	}
 
-	if out, err := exec.Command("lspci").Output(); err == nil {
+	if out, err := sys.Output(ctx, "lspci", "-nn"); err == nil {
 		scanner := bufio.NewScanner(bytes.NewReader(out))
 		for scanner.Scan() {
 			line := scanner.Text()
 			if (strings.Contains(line, "VGA") || strings.Contains(line, "3D")) && !strings.Contains(line, "DRAM Controller") {
-				if match := reIntelLspci.FindStringSubmatch(line); len(match) > 1 {
-					return strings.TrimSpace(match[1])
-				}
-				if match := reNaviLspci.FindStringSubmatch(line); len(match) > 1 {
-					return strings.TrimSpace(match[1])
-				}
-
-				parts := strings.SplitN(line, ": ", 3)
-				if len(parts) > 2 {
-					gpu := strings.TrimSpace(parts[2])
-					gpu = strings.Replace(gpu, "Advanced Micro Devices, Inc.", "", -1)
-					gpu = strings.Replace(gpu, "NVIDIA Corporation", "", -1)
-					gpu = strings.Replace(gpu, "Intel Corporation", "", -1)
-					gpu = regexp.MustCompile(`\[.*?\]`).ReplaceAllString(gpu, "")
-					gpu = regexp.MustCompile(`\(rev [0-9a-fA-F]+\)`).ReplaceAllString(gpu, "")
-					return strings.TrimSpace(gpu)
+				if model := gpuModelFromLspciLine(line, ""); model != "" {
+					gpus = append(gpus, GPUDetails{Model: model})
 				}
 			}
 		}
 	}
-
-	return "Unknown GPU"
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(gpus) == 0 {
+		return nil, modules.ErrUnavailable
+	}
+	return gpus, nil
 }
@@ -0,0 +1,142 @@
+package hardware
+
+import (
+	"asf/system"
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGPUModelFromLspciLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		vendor string
+		want   string
+	}{
+		{
+			line:   "00:02.0 VGA compatible controller: Intel Corporation Alder Lake-P GT2 [Iris Xe Graphics] (rev 0c)",
+			vendor: "Intel",
+			want:   "Iris Xe Graphics",
+		},
+		{
+			line:   "00:02.0 VGA compatible controller: Intel Corporation CometLake-H GT2 [UHD Graphics] (rev 05)",
+			vendor: "",
+			want:   "UHD Graphics",
+		},
+		{
+			line:   "03:00.0 VGA compatible controller: Advanced Micro Devices, Inc. [AMD/ATI] Navi 21 [Radeon RX 6800/6800 XT / 6900 XT] (rev c1)",
+			vendor: "AMD",
+			want:   "Radeon RX 6800/6800 XT / 6900 XT",
+		},
+		{
+			line:   "01:00.0 VGA compatible controller [0300]: NVIDIA Corporation GA104 [GeForce RTX 3070] [10de:2484] (rev a1)",
+			vendor: "NVIDIA",
+			want:   "GeForce RTX 3070",
+		},
+		{
+			line:   "05:00.0 VGA compatible controller [0300]: Advanced Micro Devices, Inc. [AMD/ATI] Cezanne [Radeon Vega Series / Radeon Vega Mobile Series] [1002:1638] (rev c6)",
+			vendor: "AMD",
+			want:   "Radeon Vega Series / Radeon Vega Mobile Series",
+		},
+		{
+			line:   "00:02.0 VGA compatible controller [0300]: Intel Corporation Device [8086:a7a0] (rev 04)",
+			vendor: "Intel",
+			want:   "",
+		},
+		{
+			line:   "00:02.0 VGA compatible controller: Intel Corporation Device a7a0 (rev 04)",
+			vendor: "",
+			want:   "",
+		},
+		{
+			line:   "00:0f.0 VGA compatible controller: VMware SVGA II Adapter",
+			vendor: "",
+			want:   "VMware SVGA II Adapter",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := gpuModelFromLspciLine(tt.line, tt.vendor); got != tt.want {
+			t.Errorf("gpuModelFromLspciLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestMapPciVendorIDToName(t *testing.T) {
+	for id, want := range map[string]string{
+		"1002": "AMD",
+		"10de": "NVIDIA",
+		"8086": "Intel",
+		"1af4": "VMware",
+		"1234": "Vendor:1234",
+	} {
+		if got := mapPciVendorIDToName(id); got != want {
+			t.Errorf("mapPciVendorIDToName(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+// replay zwraca system odtwarzający polecenia nagrane przez system.Recorder na danej maszynie
+// (testdata/commands/<maszyna>). Polecenia bez nagrania zachowują się jak nieobecne w PATH.
+func replay(fsys fstest.MapFS, machine string) *system.System {
+	return system.FromFS(fsys).WithRunner(system.Replayer{Dir: filepath.Join("testdata", "commands", machine)})
+}
+
+func TestGetGPUInfo(t *testing.T) {
+	// Laptop z iGPU Intela i dGPU NVIDII; sysfs podaje ID, lspci nazwy modeli.
+	hybrid := fstest.MapFS{
+		"sys/class/drm/card0/device/vendor":           {Data: []byte("0x8086\n")},
+		"sys/class/drm/card0/device/device":           {Data: []byte("0x46a6\n")},
+		"sys/class/drm/card1/device/vendor":           {Data: []byte("0x10de\n")},
+		"sys/class/drm/card1/device/device":           {Data: []byte("0x25a0\n")},
+		"sys/class/drm/card1/device/subsystem_vendor": {Data: []byte("0x1043\n")},
+		"sys/class/drm/card1/device/subsystem_device": {Data: []byte("0x13a4\n")},
+	}
+
+	tests := []struct {
+		machine string
+		fsys    fstest.MapFS
+		want    []GPUDetails
+	}{
+		{
+			machine: "amd-desktop",
+			want:    []GPUDetails{{Model: "AMD Radeon RX 6800 XT"}},
+		},
+		{
+			machine: "intel-nvidia-laptop",
+			fsys:    hybrid,
+			want: []GPUDetails{
+				{Vendor: "Intel", Model: "Iris Xe Graphics", PCI_ID: "8086:46a6"},
+				{Vendor: "NVIDIA", Model: "GeForce RTX 3050 Mobile", PCI_ID: "10de:25a0", SubsystemID: "1043:13a4"},
+			},
+		},
+		{
+			// Układ nowszy niż pci.ids: lspci zna tylko ID, więc model zostaje pusty.
+			machine: "intel-raptor-lake-old-pciids",
+			fsys: fstest.MapFS{
+				"sys/class/drm/card0/device/vendor": {Data: []byte("0x8086\n")},
+				"sys/class/drm/card0/device/device": {Data: []byte("0xa7a0\n")},
+			},
+			want: []GPUDetails{{Vendor: "Intel", PCI_ID: "8086:a7a0"}},
+		},
+		{
+			// vulkaninfo kończy się błędem bez sterownika ICD, a kontener nie widzi /sys/class/drm.
+			machine: "nvidia-desktop-no-icd",
+			want:    []GPUDetails{{Model: "GeForce RTX 3070"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.machine, func(t *testing.T) {
+			got, err := GetGPUInfo(context.Background(), replay(tt.fsys, tt.machine))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetGPUInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package hardware
+
+import (
+	"asf/modules"
+	"asf/system"
+	"context"
+)
+
+func cachedMemoryInfo(ctx context.Context, sys *system.System) (MemoryInfo, error) {
+	return modules.Cached(ctx, "hardware.meminfo", func(context.Context) (MemoryInfo, error) {
+		return GetMemoryInfo(sys)
+	})
+}
+
+func init() {
+	modules.Register(modules.Func("battery", "Battery", modules.Data(GetBatteryInfo)))
+	modules.Register(modules.Func("cpu", "CPU", modules.Data(GetCPUInfo)))
+	modules.Register(modules.Func("disk", "Disk", func(ctx context.Context, sys *system.System) (modules.Result, error) {
+		disks, err := GetDiskInfo(sys, modules.Options[DiskOptions](ctx, "disk"))
+		if err != nil {
+			return modules.Result{}, err
+		}
+		return modules.Result{Data: disks}, nil
+	}))
+	modules.Register(modules.Func("display", "Display", modules.Data(GetDisplayInfo)))
+	modules.Register(modules.Func("gpu", "GPU", modules.DataContext(GetGPUInfo)))
+	modules.Register(modules.Func("network", "Network", func(ctx context.Context, sys *system.System) (modules.Result, error) {
+		ifaces, err := GetNetworkInfo(sys, modules.Options[NetworkOptions](ctx, "network"))
+		if err != nil {
+			return modules.Result{}, err
+		}
+		return modules.Result{Data: ifaces}, nil
+	}))
+	modules.Register(modules.Func("ram", "RAM", func(ctx context.Context, sys *system.System) (modules.Result, error) {
+		info, err := cachedMemoryInfo(ctx, sys)
+		if err != nil {
+			return modules.Result{}, err
+		}
+		if info.TotalBytes == 0 {
+			return modules.Result{}, modules.ErrUnavailable
+		}
+		return modules.Result{Data: info.RAM()}, nil
+	}))
+	modules.Register(modules.Func("swap", "Swap", func(ctx context.Context, sys *system.System) (modules.Result, error) {
+		info, err := cachedMemoryInfo(ctx, sys)
+		if err != nil {
+			return modules.Result{}, err
+		}
+		if info.SwapTotalBytes == 0 {
+			return modules.Result{}, modules.ErrUnavailable
+		}
+		return modules.Result{Data: info.Swap()}, nil
+	}))
+}
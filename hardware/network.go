@@ -0,0 +1,149 @@
+package hardware
+
+import (
+	"asf/modules"
+	"asf/system"
+	"fmt"
+	"net"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// NetworkOptions to ustawienia modułu network z pola "network" konfiguracji.
+type NetworkOptions struct {
+	// Hide to wzorce nazw ukrywanych interfejsów w składni path.Match, np. "lo" albo "docker*".
+	// Brak pola oznacza DefaultHiddenInterfaces, pusta lista pokazuje wszystkie.
+	Hide []string `json:"hide,omitempty"`
+	// Families to pokazywane rodziny adresów: "ipv4", "ipv6"; pusta lista oznacza obie.
+	Families []string `json:"families,omitempty"`
+}
+
+// DefaultHiddenInterfaces to interfejsy, które zwykle nie interesują nikogo w fetchu:
+// pętla zwrotna i wirtualne interfejsy kontenerów. Pętla jest porównywana dokładnie,
+// żeby nie ukrywać np. lowpan0.
+var DefaultHiddenInterfaces = []string{"lo", "docker*", "veth*", "br-*"}
+
+type NetworkInterface struct {
+	Name string `json:"name"`
+	// State to operstate z sysfs, np. "up", "down", "dormant".
+	State string `json:"state"`
+	// SpeedMbps to prędkość łącza w Mb/s; 0, gdy nieznana (Wi-Fi, interfejs wyłączony).
+	SpeedMbps int      `json:"speed_mbps,omitempty"`
+	MAC       string   `json:"mac,omitempty"`
+	Wireless  bool     `json:"wireless"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+func (i NetworkInterface) Up() bool {
+	return i.State == "up"
+}
+
+// GetNetworkInfo zwraca interfejsy z /sys/class/net. Adresy pochodzą z sys.InterfaceAddrs,
+// więc dla --sysroot interfejsy są pokazywane bez adresów.
+func GetNetworkInfo(sys *system.System, opts NetworkOptions) ([]NetworkInterface, error) {
+	if runtime.GOOS != "linux" {
+		return nil, modules.ErrUnavailable
+	}
+
+	hide := opts.Hide
+	if hide == nil {
+		hide = DefaultHiddenInterfaces
+	}
+	for _, pattern := range hide {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("błędny wzorzec interfejsu %q: %v", pattern, err)
+		}
+	}
+	ipv4, ipv6, err := addressFamilies(opts.Families)
+	if err != nil {
+		return nil, err
+	}
+
+	netPath := "/sys/class/net"
+	entries, err := sys.ReadDir(netPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", modules.ErrUnavailable, err)
+	}
+
+	var ifaces []NetworkInterface
+	for _, entry := range entries {
+		name := entry.Name()
+		if matchAny(name, hide) {
+			continue
+		}
+		dir := path.Join(netPath, name)
+		// bonding_masters i podobne pliki leżą obok interfejsów, ale nie mają operstate.
+		state, err := sys.ReadFile(path.Join(dir, "operstate"))
+		if err != nil {
+			continue
+		}
+
+		iface := NetworkInterface{
+			Name:     name,
+			State:    strings.TrimSpace(string(state)),
+			MAC:      readTrimmed(sys, path.Join(dir, "address")),
+			Wireless: sys.Exists(path.Join(dir, "wireless")) || sys.Exists(path.Join(dir, "phy80211")),
+		}
+		// Jądro zwraca -1 albo błąd odczytu, gdy prędkość jest nieznana.
+		if speed, err := strconv.Atoi(readTrimmed(sys, path.Join(dir, "speed"))); err == nil && speed > 0 {
+			iface.SpeedMbps = speed
+		}
+
+		addrs, err := sys.InterfaceAddrs(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			ip, _, err := net.ParseCIDR(addr)
+			if err != nil {
+				continue
+			}
+			if isV4 := ip.To4() != nil; (isV4 && ipv4) || (!isV4 && ipv6) {
+				iface.Addresses = append(iface.Addresses, addr)
+			}
+		}
+		ifaces = append(ifaces, iface)
+	}
+
+	if len(ifaces) == 0 {
+		return nil, modules.ErrUnavailable
+	}
+	return ifaces, nil
+}
+
+func addressFamilies(families []string) (ipv4, ipv6 bool, err error) {
+	if len(families) == 0 {
+		return true, true, nil
+	}
+	for _, f := range families {
+		switch strings.ToLower(f) {
+		case "ipv4", "inet":
+			ipv4 = true
+		case "ipv6", "inet6":
+			ipv6 = true
+		default:
+			return false, false, fmt.Errorf("nieznana rodzina adresów %q (dostępne: ipv4, ipv6)", f)
+		}
+	}
+	return ipv4, ipv6, nil
+}
+
+// matchAny sprawdza, czy nazwa pasuje do któregoś ze wzorców; wzorce są sprawdzone wcześniej.
+func matchAny(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func readTrimmed(sys *system.System, name string) string {
+	data, err := sys.ReadFile(name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,121 @@
+package hardware
+
+import (
+	"asf/system"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGetNetworkInfo(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sys/class/net/bonding_masters":      {Data: []byte("\n")},
+		"sys/class/net/docker0/operstate":    {Data: []byte("down\n")},
+		"sys/class/net/enp3s0/operstate":     {Data: []byte("up\n")},
+		"sys/class/net/enp3s0/address":       {Data: []byte("a8:a1:59:12:34:56\n")},
+		"sys/class/net/enp3s0/speed":         {Data: []byte("1000\n")},
+		"sys/class/net/lo/operstate":         {Data: []byte("unknown\n")},
+		"sys/class/net/lowpan0/operstate":    {Data: []byte("down\n")},
+		"sys/class/net/veth1a2b3c/operstate": {Data: []byte("up\n")},
+		"sys/class/net/wlan0/operstate":      {Data: []byte("up\n")},
+		"sys/class/net/wlan0/address":        {Data: []byte("3c:21:9c:ab:cd:ef\n")},
+		"sys/class/net/wlan0/speed":          {Data: []byte("-1\n")},
+		"sys/class/net/wlan0/wireless":       {Mode: fs.ModeDir | 0755},
+	}
+	addrs := map[string][]string{
+		"enp3s0": {"192.168.1.10/24", "fe80::aaa1:59ff:fe12:3456/64"},
+		"lo":     {"127.0.0.1/8", "::1/128"},
+		"wlan0":  {"192.168.1.11/24"},
+	}
+	sys := system.FromFS(fsys).WithAddrs(func(name string) ([]string, error) { return addrs[name], nil })
+
+	enp3s0 := NetworkInterface{Name: "enp3s0", State: "up", SpeedMbps: 1000, MAC: "a8:a1:59:12:34:56"}
+	wlan0 := NetworkInterface{Name: "wlan0", State: "up", MAC: "3c:21:9c:ab:cd:ef", Wireless: true}
+	with := func(iface NetworkInterface, addrs ...string) NetworkInterface {
+		iface.Addresses = addrs
+		return iface
+	}
+
+	tests := []struct {
+		name string
+		opts NetworkOptions
+		want []NetworkInterface
+	}{
+		{
+			name: "default",
+			want: []NetworkInterface{
+				with(enp3s0, "192.168.1.10/24", "fe80::aaa1:59ff:fe12:3456/64"),
+				{Name: "lowpan0", State: "down"},
+				with(wlan0, "192.168.1.11/24"),
+			},
+		},
+		{
+			name: "ipv6 only",
+			opts: NetworkOptions{Families: []string{"ipv6"}},
+			want: []NetworkInterface{
+				with(enp3s0, "fe80::aaa1:59ff:fe12:3456/64"),
+				{Name: "lowpan0", State: "down"},
+				wlan0,
+			},
+		},
+		{
+			name: "hide nothing",
+			opts: NetworkOptions{Hide: []string{}, Families: []string{"ipv4"}},
+			want: []NetworkInterface{
+				{Name: "docker0", State: "down"},
+				with(enp3s0, "192.168.1.10/24"),
+				{Name: "lo", State: "unknown", Addresses: []string{"127.0.0.1/8"}},
+				{Name: "lowpan0", State: "down"},
+				{Name: "veth1a2b3c", State: "up"},
+				with(wlan0, "192.168.1.11/24"),
+			},
+		},
+		{
+			name: "hide patterns",
+			opts: NetworkOptions{Hide: []string{"lo*", "wlan?"}, Families: []string{"ipv4"}},
+			want: []NetworkInterface{
+				{Name: "docker0", State: "down"},
+				with(enp3s0, "192.168.1.10/24"),
+				{Name: "veth1a2b3c", State: "up"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetNetworkInfo(sys, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetNetworkInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNetworkInfoUnknownFamily(t *testing.T) {
+	fsys := fstest.MapFS{"sys/class/net/eth0/operstate": {Data: []byte("up\n")}}
+	if _, err := GetNetworkInfo(system.FromFS(fsys), NetworkOptions{Families: []string{"ipx"}}); err == nil {
+		t.Error("expected error for unknown address family")
+	}
+}
+
+func TestGetNetworkInfoBadHidePattern(t *testing.T) {
+	fsys := fstest.MapFS{"sys/class/net/eth0/operstate": {Data: []byte("up\n")}}
+	if _, err := GetNetworkInfo(system.FromFS(fsys), NetworkOptions{Hide: []string{"eth["}}); err == nil {
+		t.Error("expected error for malformed hide pattern")
+	}
+}
+
+func TestGetNetworkInfoSysrootHasNoAddresses(t *testing.T) {
+	fsys := fstest.MapFS{"sys/class/net/eth0/operstate": {Data: []byte("up\n")}}
+	got, err := GetNetworkInfo(system.FromFS(fsys), NetworkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []NetworkInterface{{Name: "eth0", State: "up"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetNetworkInfo() = %+v, want %+v", got, want)
+	}
+}
@@ -1,53 +1,87 @@
 package hardware
 
 import (
+	"asf/modules"
+	"asf/system"
 	"bufio"
 	"bytes"
 	"fmt"
-	"io/ioutil"
 	"runtime"
 	"strings"
 )
 
-func GetMemoryAndSwapInfo() (string, string) {
-	if runtime.GOOS == "linux" {
-		if data, err := ioutil.ReadFile("/proc/meminfo"); err == nil {
-			var totalMem, availableMem, totalSwap, freeSwap uint64
-			scanner := bufio.NewScanner(bytes.NewReader(data))
-			for scanner.Scan() {
-				line := scanner.Text()
-				if strings.HasPrefix(line, "MemTotal:") {
-					fmt.Sscanf(line, "MemTotal: %d kB", &totalMem)
-				} else if strings.HasPrefix(line, "MemAvailable:") {
-					fmt.Sscanf(line, "MemAvailable: %d kB", &availableMem)
-				} else if strings.HasPrefix(line, "SwapTotal:") {
-					fmt.Sscanf(line, "SwapTotal: %d kB", &totalSwap)
-				} else if strings.HasPrefix(line, "SwapFree:") {
-					fmt.Sscanf(line, "SwapFree: %d kB", &freeSwap)
-				}
-			}
-
-			memInfo := "unknown"
-			if totalMem > 0 {
-				usedMem := totalMem - availableMem
-				memInfo = fmt.Sprintf("%.1fGB / %.1fGB (%.1f%%)",
-					float64(usedMem)/1024/1024,
-					float64(totalMem)/1024/1024,
-					float64(usedMem)/float64(totalMem)*100)
-			}
-
-			swapInfo := "unknown"
-			if totalSwap > 0 {
-				usedSwap := totalSwap - freeSwap
-				swapInfo = fmt.Sprintf("%.1fGB / %.1fGB (%.1f%%)",
-					float64(usedSwap)/1024/1024,
-					float64(totalSwap)/1024/1024,
-					float64(usedSwap)/float64(totalSwap)*100)
-			} else {
-				swapInfo = "-"
-			}
-			return memInfo, swapInfo
+type MemoryInfo struct {
+	TotalBytes     uint64 `json:"total_bytes"`
+	AvailableBytes uint64 `json:"available_bytes"`
+	SwapTotalBytes uint64 `json:"swap_total_bytes"`
+	SwapFreeBytes  uint64 `json:"swap_free_bytes"`
+}
+
+// Usage opisuje zajętość zasobu (RAM, swap).
+type Usage struct {
+	UsedBytes  uint64  `json:"used_bytes"`
+	TotalBytes uint64  `json:"total_bytes"`
+	Percent    float64 `json:"percent"`
+}
+
+func NewUsage(used, total uint64) Usage {
+	u := Usage{UsedBytes: used, TotalBytes: total}
+	if total > 0 {
+		u.Percent = float64(used) / float64(total) * 100
+	}
+	return u
+}
+
+func (m MemoryInfo) RAM() Usage {
+	return NewUsage(m.TotalBytes-m.AvailableBytes, m.TotalBytes)
+}
+
+func (m MemoryInfo) Swap() Usage {
+	return NewUsage(m.SwapTotalBytes-m.SwapFreeBytes, m.SwapTotalBytes)
+}
+
+func GetMemoryInfo(sys *system.System) (MemoryInfo, error) {
+	var info MemoryInfo
+	if runtime.GOOS != "linux" {
+		return info, modules.ErrUnavailable
+	}
+
+	data, err := sys.ReadFile("/proc/meminfo")
+	if err != nil {
+		return info, fmt.Errorf("nie udało się odczytać /proc/meminfo: %w", err)
+	}
+
+	hasAvailable := false
+	var memFree, buffers, cached uint64
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "MemTotal:") {
+			fmt.Sscanf(line, "MemTotal: %d kB", &info.TotalBytes)
+		} else if strings.HasPrefix(line, "MemAvailable:") {
+			fmt.Sscanf(line, "MemAvailable: %d kB", &info.AvailableBytes)
+			hasAvailable = true
+		} else if strings.HasPrefix(line, "MemFree:") {
+			fmt.Sscanf(line, "MemFree: %d kB", &memFree)
+		} else if strings.HasPrefix(line, "Buffers:") {
+			fmt.Sscanf(line, "Buffers: %d kB", &buffers)
+		} else if strings.HasPrefix(line, "Cached:") {
+			fmt.Sscanf(line, "Cached: %d kB", &cached)
+		} else if strings.HasPrefix(line, "SwapTotal:") {
+			fmt.Sscanf(line, "SwapTotal: %d kB", &info.SwapTotalBytes)
+		} else if strings.HasPrefix(line, "SwapFree:") {
+			fmt.Sscanf(line, "SwapFree: %d kB", &info.SwapFreeBytes)
 		}
 	}
-	return "unknown", "unknown"
+
+	// Jądra starsze niż 3.14 nie mają MemAvailable; przybliżamy je tak jak dawniej robił to free(1).
+	if !hasAvailable {
+		info.AvailableBytes = min(memFree+buffers+cached, info.TotalBytes)
+	}
+
+	info.TotalBytes *= 1024
+	info.AvailableBytes *= 1024
+	info.SwapTotalBytes *= 1024
+	info.SwapFreeBytes *= 1024
+	return info, nil
 }
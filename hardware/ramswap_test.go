@@ -0,0 +1,65 @@
+package hardware
+
+import (
+	"asf/system"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGetMemoryInfo(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/meminfo": {Data: []byte(`MemTotal:       16303428 kB
+MemFree:         8123456 kB
+MemAvailable:   12000000 kB
+Buffers:          200000 kB
+Cached:          3000000 kB
+SwapTotal:       4194300 kB
+SwapFree:        4094300 kB
+`)},
+	}
+
+	info, err := GetMemoryInfo(system.FromFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MemoryInfo{
+		TotalBytes:     16303428 * 1024,
+		AvailableBytes: 12000000 * 1024,
+		SwapTotalBytes: 4194300 * 1024,
+		SwapFreeBytes:  4094300 * 1024,
+	}
+	if info != want {
+		t.Errorf("GetMemoryInfo() = %+v, want %+v", info, want)
+	}
+	if used := info.Swap().UsedBytes; used != 100000*1024 {
+		t.Errorf("Swap().UsedBytes = %d", used)
+	}
+}
+
+func TestGetMemoryInfoWithoutMemAvailable(t *testing.T) {
+	// Jądro 3.10 (np. CentOS 7 w starszych wydaniach) nie ma pola MemAvailable.
+	fsys := fstest.MapFS{
+		"proc/meminfo": {Data: []byte(`MemTotal:        1000000 kB
+MemFree:          300000 kB
+Buffers:           50000 kB
+Cached:           150000 kB
+SwapTotal:             0 kB
+SwapFree:              0 kB
+`)},
+	}
+
+	info, err := GetMemoryInfo(system.FromFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.AvailableBytes != 500000*1024 {
+		t.Errorf("AvailableBytes = %d, want %d", info.AvailableBytes, 500000*1024)
+	}
+	ram := info.RAM()
+	if ram.Percent != 50 {
+		t.Errorf("RAM().Percent = %v, want 50", ram.Percent)
+	}
+	if info.Swap().TotalBytes != 0 || info.Swap().Percent != 0 {
+		t.Errorf("Swap() = %+v, want zero usage", info.Swap())
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"asf/fetch"
+	"asf/modules"
+	"asf/present"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+type jsonModule struct {
+	Name     string `json:"name"`
+	Label    string `json:"label"`
+	Value    any    `json:"value,omitempty"`
+	TimedOut bool   `json:"timed_out,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type jsonReport struct {
+	Modules []jsonModule `json:"modules"`
+}
+
+// writeJSON wypisuje raport jako JSON, bez logo i kolorów.
+// Moduły bez danych (modules.ErrUnavailable) są pomijane, tak jak w tabeli.
+func writeJSON(w io.Writer, report fetch.Report) error {
+	out := jsonReport{Modules: []jsonModule{}}
+	for _, entry := range report.Entries {
+		m := jsonModule{Name: entry.Name, Label: entry.Label}
+		switch {
+		case entry.TimedOut():
+			m.TimedOut = true
+		case errors.Is(entry.Err, modules.ErrUnavailable):
+			continue
+		case entry.Err != nil:
+			m.Error = entry.Err.Error()
+		default:
+			m.Value = present.JSONValue(entry.Data)
+		}
+		out.Modules = append(out.Modules, m)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
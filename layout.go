@@ -0,0 +1,294 @@
+package main
+
+import (
+	"asf/config"
+	"asf/term"
+	"asf/theme"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// logoPosition to położenie logo względem tabeli.
+type logoPosition int
+
+const (
+	logoLeft logoPosition = iota
+	logoTop
+	logoRight
+	// logoNone pokazuje samą tabelę.
+	logoNone
+)
+
+// verticalAlign to wyrównanie niższej z dwóch kolumn (logo albo tabeli) względem wyższej.
+type verticalAlign int
+
+const (
+	alignTop verticalAlign = iota
+	alignMiddle
+	alignBottom
+)
+
+// offset zwraca, o ile linii przesunąć w dół kolumnę o wysokości height obok kolumny o wysokości total.
+func (a verticalAlign) offset(height, total int) int {
+	switch a {
+	case alignMiddle:
+		return (total - height) / 2
+	case alignBottom:
+		return total - height
+	}
+	return 0
+}
+
+// boxStyle to znaki ramki wokół tabeli: rogi (lewy górny, prawy górny, lewy dolny, prawy dolny),
+// linia pozioma i pionowa.
+type boxStyle struct {
+	topLeft, topRight, bottomLeft, bottomRight string
+	horizontal, vertical                       string
+}
+
+var boxStyles = map[string]boxStyle{
+	"single":  {"┌", "┐", "└", "┘", "─", "│"},
+	"rounded": {"╭", "╮", "╰", "╯", "─", "│"},
+	"double":  {"╔", "╗", "╚", "╝", "═", "║"},
+	"heavy":   {"┏", "┓", "┗", "┛", "━", "┃"},
+}
+
+// layoutOptions opisuje układ wyjścia: gdzie jest logo, ile ma miejsca tabela i co zrobić
+// ze zbyt długimi wartościami.
+type layoutOptions struct {
+	// Width to szerokość terminalu w kolumnach; 0 oznacza brak ograniczenia (np. wyjście do pliku).
+	Width int
+	// Wrap zawija zbyt długie wartości pod etykietą zamiast obcinać je wielokropkiem.
+	Wrap bool
+	Logo logoPosition
+	// Align wyrównuje w pionie niższą kolumnę, gdy logo i tabela są obok siebie.
+	Align verticalAlign
+	// Box to ramka wokół tabeli; nil oznacza brak ramki.
+	Box *boxStyle
+	// Gap to odstęp w kolumnach między logo a tabelą, gdy są obok siebie.
+	// Logo nad tabelą jest zawsze oddzielone jedną pustą linią.
+	Gap int
+	// PaddingTop, PaddingBottom i PaddingLeft to puste linie nad i pod wyjściem oraz wcięcie z lewej.
+	PaddingTop, PaddingBottom, PaddingLeft int
+}
+
+// defaultLayout odpowiada dotychczasowemu wyglądowi: logo po lewej, 4 kolumny odstępu,
+// po jednej pustej linii nad i pod.
+var defaultLayout = layoutOptions{Logo: logoLeft, Gap: 4, PaddingTop: 1, PaddingBottom: 1}
+
+const (
+	// noLogoIndent to wcięcie tabeli, gdy logo po lewej jest wyłączone.
+	noLogoIndent = 20
+	// minValueWidth to najmniejsza szerokość kolumny wartości, przy której logo zostaje obok tabeli;
+	// przy węższym terminalu logo trafia nad tabelę albo znika.
+	minValueWidth = 16
+)
+
+// block to prostokąt tekstu o znanej szerokości w kolumnach.
+type block struct {
+	lines []string
+	width int
+}
+
+func newBlock(lines []string) block {
+	b := block{lines: lines}
+	for _, line := range lines {
+		b.width = max(b.width, term.Width(line))
+	}
+	return b
+}
+
+// line zwraca i-tą linię dopełnioną spacjami do szerokości bloku (albo same spacje poza blokiem).
+func (b block) line(i int) string {
+	line := ""
+	if i >= 0 && i < len(b.lines) {
+		line = b.lines[i]
+	}
+	return line + strings.Repeat(" ", b.width-term.Width(line))
+}
+
+// renderText rysuje logo i tabelę informacji w układzie z opts. logo == nil oznacza brak logo,
+// zerowa paleta wyłącza sekwencje ANSI.
+func renderText(w io.Writer, infoPairs []infoPair, logo []string, pal theme.Palette, opts layoutOptions) {
+	reset := pal.Reset
+
+	// writeLine obcina linię do szerokości terminalu; na nic węższego nie mamy już innej rady.
+	writeLine := func(line string) {
+		line = strings.TrimRight(strings.Repeat(" ", opts.PaddingLeft)+line, " ")
+		if opts.Width > 0 && term.Width(line) > opts.Width {
+			line = term.Truncate(line, opts.Width) + reset
+		}
+		fmt.Fprintf(w, "%s\n", line)
+	}
+
+	maxLabelLen := 0
+	for _, pair := range infoPairs {
+		if pair.Kind == lineInfo {
+			maxLabelLen = max(maxLabelLen, term.Width(pair.title()))
+		}
+	}
+	// Etykieta, spacja, separator i spacja, a w ramce jeszcze "│ " i " │".
+	tableFixed := maxLabelLen + 3
+	if opts.Box != nil {
+		tableFixed += 4
+	}
+
+	position := opts.Logo
+	if logo == nil && position != logoLeft {
+		position = logoNone
+	}
+	var logoBlock block
+	if logo != nil && position != logoNone {
+		colored := make([]string, len(logo))
+		for i, line := range logo {
+			colored[i] = pal.Logo + line + reset
+		}
+		logoBlock = newBlock(colored)
+	}
+
+	available := 0
+	if opts.Width > 0 {
+		available = opts.Width - opts.PaddingLeft
+	}
+	if available > 0 && logo != nil && (position == logoLeft || position == logoRight) &&
+		logoBlock.width+opts.Gap+tableFixed+minValueWidth > available {
+		// Za wąsko na dwie kolumny: logo nad tabelą, jeśli w ogóle się mieści.
+		position = logoTop
+		if logoBlock.width > available {
+			position = logoNone
+		}
+	}
+
+	indent := 0
+	switch {
+	case position == logoLeft && logo == nil:
+		// Bez logo tabela zostaje tam, gdzie byłaby obok logo domyślnej szerokości.
+		indent = noLogoIndent + opts.Gap
+		if available > 0 && indent+tableFixed+minValueWidth > available {
+			indent = 0
+		}
+	case position == logoLeft || position == logoRight:
+		indent = logoBlock.width + opts.Gap
+	}
+
+	valueWidth := 0
+	if available > 0 {
+		valueWidth = max(available-indent-tableFixed, 1)
+	}
+	info := newBlock(buildInfoLines(infoPairs, maxLabelLen, valueWidth, opts.Wrap, pal))
+	if opts.Box != nil {
+		info = boxed(info, *opts.Box, pal)
+	}
+
+	for i := 0; i < opts.PaddingTop; i++ {
+		writeLine("")
+	}
+
+	gap := strings.Repeat(" ", opts.Gap)
+	height := max(len(logoBlock.lines), len(info.lines))
+	logoOffset := opts.Align.offset(len(logoBlock.lines), height)
+	infoOffset := opts.Align.offset(len(info.lines), height)
+	switch position {
+	case logoLeft:
+		if logo == nil {
+			for _, line := range info.lines {
+				writeLine(strings.Repeat(" ", indent) + line)
+			}
+			break
+		}
+		for i := 0; i < height; i++ {
+			writeLine(logoBlock.line(i-logoOffset) + gap + info.line(i-infoOffset))
+		}
+	case logoRight:
+		for i := 0; i < height; i++ {
+			writeLine(info.line(i-infoOffset) + gap + logoBlock.line(i-logoOffset))
+		}
+	case logoTop:
+		for _, line := range logoBlock.lines {
+			writeLine(line)
+		}
+		writeLine("")
+		fallthrough
+	case logoNone:
+		for _, line := range info.lines {
+			writeLine(line)
+		}
+	}
+
+	for i := 0; i < opts.PaddingBottom; i++ {
+		writeLine("")
+	}
+}
+
+// boxed otacza blok ramką w kolorze separatora.
+func boxed(b block, style boxStyle, pal theme.Palette) block {
+	color := func(s string) string { return pal.Separator + s + pal.Reset }
+	horizontal := strings.Repeat(style.horizontal, b.width+2)
+
+	lines := make([]string, 0, len(b.lines)+2)
+	lines = append(lines, color(style.topLeft+horizontal+style.topRight))
+	for i := range b.lines {
+		lines = append(lines, color(style.vertical)+" "+b.line(i)+" "+color(style.vertical))
+	}
+	lines = append(lines, color(style.bottomLeft+horizontal+style.bottomRight))
+	return block{lines: lines, width: b.width + 4}
+}
+
+// layoutFromConfig odczytuje układ z konfiguracji; pola nieustawione mają wartości z defaultLayout.
+func layoutFromConfig(cfg config.Config) (layoutOptions, error) {
+	opts := defaultLayout
+	opts.Wrap = cfg.Overflow == config.OverflowWrap
+
+	switch cfg.Layout {
+	case "", config.LayoutLeft:
+		opts.Logo = logoLeft
+	case config.LayoutTop:
+		opts.Logo = logoTop
+	case config.LayoutRight:
+		opts.Logo = logoRight
+	case config.LayoutNone:
+		opts.Logo = logoNone
+	default:
+		return defaultLayout, fmt.Errorf("nieznany układ %q (dostępne: left, top, right, none)", cfg.Layout)
+	}
+
+	switch cfg.Align {
+	case "", config.AlignTop:
+		opts.Align = alignTop
+	case config.AlignMiddle:
+		opts.Align = alignMiddle
+	case config.AlignBottom:
+		opts.Align = alignBottom
+	default:
+		return defaultLayout, fmt.Errorf("nieznane wyrównanie %q (dostępne: top, middle, bottom)", cfg.Align)
+	}
+
+	if cfg.Box != "" {
+		style, ok := boxStyles[cfg.Box]
+		if !ok {
+			return defaultLayout, fmt.Errorf("nieznany styl ramki %q (dostępne: single, rounded, double, heavy)", cfg.Box)
+		}
+		opts.Box = &style
+	}
+
+	for _, f := range []struct {
+		name  string
+		value *int
+		dst   *int
+	}{
+		{"gap", cfg.Gap, &opts.Gap},
+		{"padding_top", cfg.PaddingTop, &opts.PaddingTop},
+		{"padding_bottom", cfg.PaddingBottom, &opts.PaddingBottom},
+		{"padding_left", cfg.PaddingLeft, &opts.PaddingLeft},
+	} {
+		if f.value == nil {
+			continue
+		}
+		if *f.value < 0 {
+			return defaultLayout, fmt.Errorf("%s nie może być ujemne", f.name)
+		}
+		*f.dst = *f.value
+	}
+	return opts, nil
+}
@@ -0,0 +1,75 @@
+package logo
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	// Auto w logo_path wybiera wbudowane logo dystrybucji na podstawie os-release.
+	Auto = "auto"
+	// DistroPrefix w logo_path albo --logo wskazuje wbudowane logo po nazwie, np. "distro:arch".
+	DistroPrefix = "distro:"
+	// Fallback to logo używane, gdy dystrybucja nie ma własnego.
+	Fallback = "linux"
+)
+
+//go:embed distros/*.txt
+var distros embed.FS
+
+// distroAliases mapuje wartości ID, które nie pokrywają się z nazwą pliku.
+var distroAliases = map[string]string{
+	"opensuse-leap":       "opensuse",
+	"opensuse-tumbleweed": "opensuse",
+	"opensuse-microos":    "opensuse",
+	"suse":                "opensuse",
+	"archarm":             "arch",
+	"nixos-unstable":      "nixos",
+}
+
+// Distros zwraca nazwy wbudowanych logo.
+func Distros() []string {
+	files, _ := fs.Glob(distros, "distros/*.txt")
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, strings.TrimSuffix(path.Base(file), ".txt"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Distro zwraca wbudowane logo o podanej nazwie albo ID z os-release.
+func Distro(name string) (Logo, error) {
+	name = strings.ToLower(name)
+	if alias, ok := distroAliases[name]; ok {
+		name = alias
+	}
+	data, err := distros.ReadFile(path.Join("distros", name+".txt"))
+	if err != nil {
+		return Logo{}, fmt.Errorf("brak wbudowanego logo %q (dostępne: %s)", name, strings.Join(Distros(), ", "))
+	}
+	return Parse(strings.TrimRight(string(data), "\n"))
+}
+
+// ForOSRelease wybiera logo na podstawie pól ID i ID_LIKE z os-release; gdy żadne
+// nie pasuje, zwraca logo Fallback.
+func ForOSRelease(release map[string]string) Logo {
+	candidates := append([]string{release["ID"]}, strings.Fields(release["ID_LIKE"])...)
+	for _, id := range candidates {
+		if id == "" {
+			continue
+		}
+		if l, err := Distro(id); err == nil {
+			return l
+		}
+	}
+	l, err := Distro(Fallback)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
@@ -0,0 +1,65 @@
+package logo
+
+import (
+	"asf/term"
+	"slices"
+	"testing"
+)
+
+func TestDistrosParse(t *testing.T) {
+	names := Distros()
+	for _, want := range []string{"arch", "debian", "ubuntu", "fedora", "nixos", "alpine", "gentoo", "opensuse", "void", Fallback} {
+		if !slices.Contains(names, want) {
+			t.Errorf("brak wbudowanego logo %q", want)
+		}
+	}
+	for _, name := range names {
+		l, err := Distro(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if len(l.Lines) == 0 || l.Lines[len(l.Lines)-1] == "" {
+			t.Errorf("%s: puste logo albo pusta ostatnia linia: %q", name, l.Lines)
+		}
+		for _, line := range l.Render("", false) {
+			if w := term.Width(line); w > 40 {
+				t.Errorf("%s: linia szersza niż 40 kolumn: %q", name, line)
+			}
+		}
+	}
+}
+
+func TestForOSRelease(t *testing.T) {
+	arch, _ := Distro("arch")
+	suse, _ := Distro("opensuse")
+	fedora, _ := Distro("fedora")
+	tux, _ := Distro(Fallback)
+
+	tests := []struct {
+		name    string
+		release map[string]string
+		want    Logo
+	}{
+		{"ID", map[string]string{"ID": "arch"}, arch},
+		{"alias", map[string]string{"ID": "opensuse-tumbleweed", "ID_LIKE": "opensuse suse"}, suse},
+		{"ID_LIKE", map[string]string{"ID": "rocky", "ID_LIKE": "rhel centos fedora"}, fedora},
+		{"pochodna Archa", map[string]string{"ID": "endeavouros", "ID_LIKE": "arch"}, arch},
+		{"nieznana", map[string]string{"ID": "hannah-montana-linux"}, tux},
+		{"brak os-release", nil, tux},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ForOSRelease(tt.release)
+			if !slices.Equal(got.Lines, tt.want.Lines) {
+				t.Errorf("ForOSRelease(%v) = %q, want %q", tt.release, got.Lines, tt.want.Lines)
+			}
+		})
+	}
+}
+
+func TestDistroUnknown(t *testing.T) {
+	if _, err := Distro("templeos"); err == nil {
+		t.Error("oczekiwano błędu")
+	}
+}
@@ -0,0 +1,149 @@
+// Package logo wczytuje logo asfetch z plików tekstowych ze znacznikami kolorów.
+//
+// Plik logo to zwykły tekst. Kolory można wstawiać znacznikami ${nazwa}, gdzie nazwa to c1…c6,
+// własna nazwa zdefiniowana w nagłówku, nazwa koloru z palety ("bright_red"), numer z palety
+// 256 kolorów albo kolor szesnastkowy ("${#ff8800}"). ${reset} wraca do koloru logo z motywu.
+// Surowe sekwencje ANSI są przepuszczane bez zmian. Kolor obowiązuje do następnego znacznika,
+// także w kolejnych liniach.
+//
+// Pierwsze linie pliku mogą definiować kolory logo:
+//
+//	@colors c1=bright_blue c2=#ff8800 futro=208
+package logo
+
+import (
+	"asf/term"
+	"asf/theme"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Placeholders to znaczniki c1…c6, które nie muszą być zdefiniowane; niezdefiniowane
+// oznaczają kolor logo z motywu.
+var Placeholders = []string{"c1", "c2", "c3", "c4", "c5", "c6"}
+
+const directive = "@colors"
+
+var reTag = regexp.MustCompile(`\$\{([A-Za-z0-9_#-]+)\}`)
+
+type Logo struct {
+	// Lines to linie logo ze znacznikami, bez nagłówka.
+	Lines []string
+	// Colors przypisuje nazwom znaczników opisy kolorów (jak w theme.ParseColor).
+	Colors map[string]string
+}
+
+// Load wczytuje logo z pliku.
+func Load(path string) (Logo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Logo{}, fmt.Errorf("nie udało się wczytać logo z pliku %s: %w", path, err)
+	}
+	l, err := Parse(string(data))
+	if err != nil {
+		return Logo{}, fmt.Errorf("niepoprawne logo w pliku %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Parse odczytuje nagłówek z kolorami i sprawdza, czy wszystkie znaczniki dają się rozwiązać.
+func Parse(text string) (Logo, error) {
+	l := Logo{Colors: map[string]string{}}
+	lines := strings.Split(text, "\n")
+	for len(lines) > 0 && strings.HasPrefix(lines[0], directive+" ") {
+		for _, field := range strings.Fields(strings.TrimPrefix(lines[0], directive)) {
+			name, spec, ok := strings.Cut(field, "=")
+			if !ok || name == "" {
+				return Logo{}, fmt.Errorf("niepoprawny wpis %q w %s (oczekiwano nazwa=kolor)", field, directive)
+			}
+			if _, err := theme.ParseColor(spec); err != nil {
+				return Logo{}, fmt.Errorf("%s: %w", name, err)
+			}
+			l.Colors[name] = spec
+		}
+		lines = lines[1:]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	l.Lines = lines
+
+	for _, line := range l.Lines {
+		for _, m := range reTag.FindAllStringSubmatch(line, -1) {
+			if _, err := l.color(m[1]); err != nil {
+				return Logo{}, err
+			}
+		}
+	}
+	return l, nil
+}
+
+// WithColors zwraca kopię logo z nadpisanymi kolorami (np. z konfiguracji).
+func (l Logo) WithColors(colors map[string]string) Logo {
+	merged := make(map[string]string, len(l.Colors)+len(colors))
+	for name, spec := range l.Colors {
+		merged[name] = spec
+	}
+	for name, spec := range colors {
+		merged[name] = spec
+	}
+	l.Colors = merged
+	return l
+}
+
+// color zwraca sekwencję ANSI dla znacznika; pusta oznacza kolor logo z motywu.
+func (l Logo) color(name string) (string, error) {
+	if name == "reset" {
+		return "", nil
+	}
+	if spec, ok := l.Colors[name]; ok {
+		return theme.ParseColor(spec)
+	}
+	for _, p := range Placeholders {
+		if name == p {
+			return "", nil
+		}
+	}
+	code, err := theme.ParseColor(name)
+	if err != nil {
+		return "", fmt.Errorf("nieznany znacznik koloru ${%s}", name)
+	}
+	return code, nil
+}
+
+// Render zamienia znaczniki na sekwencje ANSI. base to kolor logo z motywu, od którego
+// zaczyna się każda linia i do którego wraca ${reset}. Gdy enabled == false, znaczniki
+// i surowe sekwencje ANSI są usuwane.
+func (l Logo) Render(base string, enabled bool) []string {
+	out := make([]string, 0, len(l.Lines))
+	active := ""
+	for _, line := range l.Lines {
+		if !enabled {
+			out = append(out, term.StripANSI(reTag.ReplaceAllString(line, "")))
+			continue
+		}
+
+		var b strings.Builder
+		if active != "" {
+			b.WriteString(active)
+		}
+		last := 0
+		for _, m := range reTag.FindAllStringSubmatchIndex(line, -1) {
+			b.WriteString(line[last:m[0]])
+			last = m[1]
+
+			code, _ := l.color(line[m[2]:m[3]])
+			active = code
+			if code == "" {
+				b.WriteString("\033[0m" + base)
+			} else {
+				b.WriteString(code)
+			}
+		}
+		b.WriteString(line[last:])
+		out = append(out, b.String())
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package logo
+
+import (
+	"asf/term"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const base = "\033[96m"
+
+func TestParseHeader(t *testing.T) {
+	l, err := Parse("@colors c1=red c2=#ff8800\n@colors futro=208\n${c1}/\\\\_/\\\\\r\n${futro}( o.o )\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"c1": "red", "c2": "#ff8800", "futro": "208"}
+	if !reflect.DeepEqual(l.Colors, want) {
+		t.Errorf("Colors = %v, want %v", l.Colors, want)
+	}
+	if len(l.Lines) != 3 || l.Lines[0] != `${c1}/\\_/\\` {
+		t.Errorf("Lines = %q", l.Lines)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{
+		"@colors c1=fioletowy\nx",
+		"@colors c1\nx",
+		"${nieznany}x",
+	} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("Parse(%q): oczekiwano błędu", text)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	l, err := Parse("@colors c1=red\n${c1}ab${c2}cd\nef${reset}gh\n${#00ff00}ij\n\033[1mkl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := l.Render(base, true)
+	want := []string{
+		"\033[31mab\033[0m" + base + "cd",
+		"ef\033[0m" + base + "gh",
+		"\033[38;2;0;255;0mij",
+		"\033[38;2;0;255;0m\033[1mkl",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Render =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestRenderColorsCarryOver(t *testing.T) {
+	l, err := Parse("${c1}ab\ncd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := l.WithColors(map[string]string{"c1": "blue"}).Render(base, true)
+	if got[1] != "\033[34mcd" {
+		t.Errorf("kolor nie przeszedł do kolejnej linii: %q", got[1])
+	}
+	if l.Colors["c1"] != "" {
+		t.Error("WithColors zmienił oryginał")
+	}
+}
+
+func TestRenderWithoutColor(t *testing.T) {
+	l, err := Parse("@colors c1=red\n${c1}日本${c2}語\n\033[35m⣿⣿\033[0m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := l.Render(base, false)
+	if want := []string{"日本語", "⣿⣿"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestMarkupDoesNotAffectWidth(t *testing.T) {
+	l, err := Parse("${c1}⣿⣿${c2}日${#123456}x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := l.WithColors(map[string]string{"c1": "red", "c2": "34"}).Render(base, true)[0]
+	if w := term.Width(line); w != 5 {
+		t.Errorf("Width(%q) = %d, want 5", line, w)
+	}
+	if strings.Contains(line, "${") {
+		t.Errorf("niezamieniony znacznik: %q", line)
+	}
+}
@@ -0,0 +1,67 @@
+package modules
+
+import (
+	"context"
+	"sync"
+)
+
+type cacheKey struct{}
+
+type cacheEntry struct {
+	once  sync.Once
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// runCache współdzieli wyniki kosztownych wywołań między modułami jednego uruchomienia,
+// np. DE i WM korzystają z jednego wykrywania środowiska, a RAM i swap z jednego odczytu /proc/meminfo.
+type runCache struct {
+	// ctx to kontekst całego uruchomienia: z limitem łącznym, ale bez limitów pojedynczych modułów,
+	// żeby limit jednego modułu nie przerywał pracy, na którą czekają inne.
+	ctx     context.Context
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// WithCache zwraca kontekst z nową, pustą pamięcią podręczną na czas jednego uruchomienia.
+func WithCache(ctx context.Context) context.Context {
+	c := &runCache{entries: map[string]*cacheEntry{}}
+	c.ctx = context.WithValue(ctx, cacheKey{}, c)
+	return c.ctx
+}
+
+// Cached wywołuje fn co najwyżej raz dla danego klucza w obrębie kontekstu utworzonego przez WithCache.
+// fn dostaje kontekst uruchomienia, a wywołujący czeka na wynik tylko do końca własnego kontekstu
+// i wtedy dostaje jego błąd (np. context.DeadlineExceeded). Bez pamięci podręcznej w kontekście
+// fn jest wywoływane za każdym razem.
+func Cached[T any](ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	c, ok := ctx.Value(cacheKey{}).(*runCache)
+	if !ok {
+		return fn(ctx)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &cacheEntry{done: make(chan struct{})}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		go func() {
+			entry.value, entry.err = fn(c.ctx)
+			close(entry.done)
+		}()
+	})
+
+	var zero T
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+	value, _ := entry.value.(T)
+	return value, entry.err
+}
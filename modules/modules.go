@@ -0,0 +1,100 @@
+package modules
+
+import (
+	"asf/system"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrUnavailable oznacza, że moduł działa poprawnie, ale nie ma nic do pokazania
+// (np. brak baterii, brak odtwarzanej muzyki, brak swapu).
+var ErrUnavailable = errors.New("brak danych")
+
+// Result przechowuje surowe dane modułu (np. hardware.CPUInfo, time.Duration, string).
+// Zamianą danych na tekst zajmuje się warstwa prezentacji (pakiet present).
+type Result struct {
+	Data any
+}
+
+// Module to pojedyncza linia informacji. Fetch czyta dane wyłącznie przez sys,
+// dzięki czemu moduł działa tak samo dla żywego systemu, jak i dla --sysroot.
+type Module interface {
+	Name() string
+	Label() string
+	Fetch(ctx context.Context, sys *system.System) (Result, error)
+}
+
+type funcModule struct {
+	name  string
+	label string
+	fetch func(ctx context.Context, sys *system.System) (Result, error)
+}
+
+func (m funcModule) Name() string  { return m.name }
+func (m funcModule) Label() string { return m.label }
+
+func (m funcModule) Fetch(ctx context.Context, sys *system.System) (Result, error) {
+	return m.fetch(ctx, sys)
+}
+
+// Func tworzy moduł z nazwy, domyślnej etykiety i funkcji pobierającej dane.
+func Func(name, label string, fetch func(ctx context.Context, sys *system.System) (Result, error)) Module {
+	return funcModule{name: name, label: label, fetch: fetch}
+}
+
+// Data opakowuje typowany getter w funkcję pobierającą modułu.
+func Data[T any](get func(sys *system.System) (T, error)) func(ctx context.Context, sys *system.System) (Result, error) {
+	return DataContext(func(_ context.Context, sys *system.System) (T, error) { return get(sys) })
+}
+
+// DataContext działa jak Data, ale dla getterów uruchamiających zewnętrzne programy,
+// które muszą respektować anulowanie kontekstu.
+func DataContext[T any](get func(ctx context.Context, sys *system.System) (T, error)) func(ctx context.Context, sys *system.System) (Result, error) {
+	return func(ctx context.Context, sys *system.System) (Result, error) {
+		value, err := get(ctx, sys)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return Result{}, ctxErr
+		}
+		if err != nil {
+			return Result{}, err
+		}
+		return Result{Data: value}, nil
+	}
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Module{}
+	names      []string
+)
+
+// Register dodaje moduł do rejestru. Zwykle wywoływane z init() pakietu, który dostarcza moduł.
+func Register(m Module) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name := m.Name()
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("modules: moduł %q jest już zarejestrowany", name))
+	}
+	registry[name] = m
+	names = append(names, name)
+}
+
+func Lookup(name string) (Module, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	m, ok := registry[name]
+	return m, ok
+}
+
+// Names zwraca nazwy wszystkich zarejestrowanych modułów w kolejności rejestracji.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]string(nil), names...)
+}
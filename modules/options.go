@@ -0,0 +1,19 @@
+package modules
+
+import "context"
+
+type optionsKey struct{}
+
+// WithOptions zwraca kontekst z ustawieniami modułów z konfiguracji, kluczowanymi nazwą modułu
+// (np. "disk": hardware.DiskOptions).
+func WithOptions(ctx context.Context, options map[string]any) context.Context {
+	return context.WithValue(ctx, optionsKey{}, options)
+}
+
+// Options zwraca ustawienia modułu name zapisane przez WithOptions. Gdy ich brak albo mają inny typ,
+// zwraca wartość zerową T, czyli ustawienia domyślne.
+func Options[T any](ctx context.Context, name string) T {
+	options, _ := ctx.Value(optionsKey{}).(map[string]any)
+	value, _ := options[name].(T)
+	return value
+}
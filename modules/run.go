@@ -0,0 +1,84 @@
+package modules
+
+import (
+	"asf/system"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"time"
+)
+
+// Outcome to wynik uruchomienia pojedynczego modułu. Err == context.DeadlineExceeded
+// oznacza, że moduł nie zmieścił się w swoim limicie czasu.
+type Outcome struct {
+	Module Module
+	Result Result
+	Err    error
+}
+
+// TimedOut zwraca true, jeśli moduł przekroczył swój limit czasu.
+func (o Outcome) TimedOut() bool {
+	return errors.Is(o.Err, context.DeadlineExceeded)
+}
+
+// Run uruchamia wszystkie moduły równolegle i zwraca wyniki w tej samej kolejności,
+// w jakiej zostały podane. timeout zwraca limit czasu dla danego modułu; zero oznacza brak limitu.
+// Moduł, który nie zareaguje na anulowanie kontekstu, nie blokuje pozostałych.
+func Run(ctx context.Context, sys *system.System, mods []Module, timeout func(name string) time.Duration) []Outcome {
+	if _, ok := ctx.Value(cacheKey{}).(*runCache); !ok {
+		ctx = WithCache(ctx)
+	}
+
+	outcomes := make([]Outcome, len(mods))
+	done := make(chan struct{}, len(mods))
+
+	for i, mod := range mods {
+		go func() {
+			outcomes[i] = runOne(ctx, sys, mod, timeout(mod.Name()))
+			done <- struct{}{}
+		}()
+	}
+	for range mods {
+		<-done
+	}
+	return outcomes
+}
+
+func runOne(ctx context.Context, sys *system.System, mod Module, timeout time.Duration) Outcome {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type fetched struct {
+		res Result
+		err error
+	}
+	ch := make(chan fetched, 1)
+	go func() {
+		res, err := mod.Fetch(ctx, sys)
+		ch <- fetched{res, err}
+	}()
+
+	select {
+	case f := <-ch:
+		return Outcome{Module: mod, Result: f.res, Err: classify(f.err)}
+	case <-ctx.Done():
+		return Outcome{Module: mod, Err: ctx.Err()}
+	}
+}
+
+// classify oznacza jako ErrUnavailable błędy, które wynikają tylko z braku źródła danych
+// na danej maszynie: brak pliku w /proc lub /sys, brak programu w PATH albo --sysroot bez poleceń.
+func classify(err error) error {
+	if err == nil || errors.Is(err, ErrUnavailable) {
+		return err
+	}
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, exec.ErrNotFound) || errors.Is(err, system.ErrNoCommands) {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package modules
+
+import (
+	"asf/system"
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestRunKeepsOrderAndTimesOut(t *testing.T) {
+	slow := Func("slow", "Slow", func(ctx context.Context, sys *system.System) (Result, error) {
+		<-ctx.Done()
+		return Result{}, ctx.Err()
+	})
+	stuck := Func("stuck", "Stuck", func(ctx context.Context, sys *system.System) (Result, error) {
+		time.Sleep(time.Hour)
+		return Result{}, nil
+	})
+	fast := Func("fast", "Fast", func(ctx context.Context, sys *system.System) (Result, error) {
+		return Result{Data: "ok"}, nil
+	})
+	missing := Func("missing", "Missing", func(ctx context.Context, sys *system.System) (Result, error) {
+		_, err := sys.ReadFile("/proc/nope")
+		return Result{}, err
+	})
+
+	sys := system.FromFS(fstest.MapFS{})
+	timeouts := map[string]time.Duration{"slow": 10 * time.Millisecond, "stuck": 10 * time.Millisecond}
+
+	start := time.Now()
+	out := Run(context.Background(), sys, []Module{slow, fast, stuck, missing}, func(name string) time.Duration {
+		return timeouts[name]
+	})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Run took %v, stuck module blocked the fetch", elapsed)
+	}
+
+	if len(out) != 4 {
+		t.Fatalf("got %d outcomes", len(out))
+	}
+	for i, name := range []string{"slow", "fast", "stuck", "missing"} {
+		if out[i].Module.Name() != name {
+			t.Errorf("outcome %d = %s, want %s", i, out[i].Module.Name(), name)
+		}
+	}
+	if !out[0].TimedOut() || !out[2].TimedOut() {
+		t.Errorf("slow/stuck should time out: %v, %v", out[0].Err, out[2].Err)
+	}
+	if out[1].Err != nil || out[1].Result.Data != "ok" {
+		t.Errorf("fast = %+v", out[1])
+	}
+	if !errors.Is(out[3].Err, ErrUnavailable) || !errors.Is(out[3].Err, fs.ErrNotExist) {
+		t.Errorf("missing file should be reported as unavailable, got %v", out[3].Err)
+	}
+}
+
+func TestCachedSharesResultWithinRun(t *testing.T) {
+	calls := 0
+	ctx := WithCache(context.Background())
+	for i := 0; i < 3; i++ {
+		v, _ := Cached(ctx, "key", func(context.Context) (int, error) {
+			calls++
+			return 7, nil
+		})
+		if v != 7 {
+			t.Fatalf("Cached() = %d", v)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+}
+
+func TestCachedIgnoresPerModuleTimeout(t *testing.T) {
+	shared := func(ctx context.Context) (string, error) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			return "wspólne", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	module := func(name string) Module {
+		return Func(name, name, func(ctx context.Context, sys *system.System) (Result, error) {
+			v, err := Cached(ctx, "shared", shared)
+			return Result{Data: v}, err
+		})
+	}
+	timeouts := map[string]time.Duration{"short": 5 * time.Millisecond}
+
+	out := Run(context.Background(), system.FromFS(fstest.MapFS{}), []Module{module("short"), module("long")}, func(name string) time.Duration {
+		return timeouts[name]
+	})
+	if !out[0].TimedOut() {
+		t.Errorf("short = %+v, want timeout", out[0])
+	}
+	if out[1].Err != nil || out[1].Result.Data != "wspólne" {
+		t.Errorf("long = %+v, want shared value", out[1])
+	}
+}
@@ -1,23 +1,22 @@
 package osinfo
 
 import (
-	"os/exec"
+	"asf/system"
+	"context"
+	"fmt"
 	"strings"
-	"sync"
 )
 
-var (
-	cachedKernel string
-	kernelOnce   sync.Once
-)
-
-func GetKernel() string {
-	kernelOnce.Do(func() {
-		if out, err := exec.Command("uname", "-r").Output(); err == nil {
-			cachedKernel = strings.TrimSpace(string(out))
-			return
+func GetKernel(ctx context.Context, sys *system.System) (string, error) {
+	if data, err := sys.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		if release := strings.TrimSpace(string(data)); release != "" {
+			return release, nil
 		}
-		cachedKernel = "unknown"
-	})
-	return cachedKernel
+	}
+
+	out, err := sys.Output(ctx, "uname", "-r")
+	if err != nil {
+		return "", fmt.Errorf("nie udało się uruchomić uname: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
 }
@@ -0,0 +1,9 @@
+package osinfo
+
+import "asf/modules"
+
+func init() {
+	modules.Register(modules.Func("os", "OS", modules.DataContext(GetOSInfo)))
+	modules.Register(modules.Func("kernel", "Kernel", modules.DataContext(GetKernel)))
+	modules.Register(modules.Func("packages", "Packages", modules.DataContext(GetPackageCount)))
+}
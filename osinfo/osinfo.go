@@ -1,38 +1,74 @@
 package osinfo
 
 import (
-	"io/ioutil"
-	"os/exec"
-	"regexp"
+	"asf/system"
+	"bufio"
+	"bytes"
+	"context"
 	"runtime"
+	"strconv"
 	"strings"
-	"sync"
 )
 
-var (
-	cachedOSInfo string
-	osInfoOnce   sync.Once
-)
+// ParseOSRelease parsuje plik os-release (KLUCZ=wartość, wartości opcjonalnie w cudzysłowach).
+func ParseOSRelease(data []byte) map[string]string {
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			} else {
+				value = value[1 : len(value)-1]
+			}
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		fields[strings.TrimSpace(key)] = value
+	}
+	return fields
+}
 
-func GetOSInfo() string {
-	osInfoOnce.Do(func() {
-		if runtime.GOOS == "linux" {
-			if data, err := ioutil.ReadFile("/etc/os-release"); err == nil {
-				re := regexp.MustCompile(`PRETTY_NAME="(.+)"`)
-				if match := re.FindStringSubmatch(string(data)); len(match) > 1 {
-					cachedOSInfo = strings.Trim(match[1], `"`)
-					return
+// GetOSRelease czyta /etc/os-release, a gdy go nie ma, /usr/lib/os-release.
+func GetOSRelease(sys *system.System) (map[string]string, error) {
+	data, err := sys.ReadFile("/etc/os-release")
+	if err != nil {
+		data, err = sys.ReadFile("/usr/lib/os-release")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ParseOSRelease(data), nil
+}
+
+func GetOSInfo(ctx context.Context, sys *system.System) (string, error) {
+	if runtime.GOOS == "linux" {
+		if release, err := GetOSRelease(sys); err == nil {
+			if name := release["PRETTY_NAME"]; name != "" {
+				return name, nil
+			}
+			if name := release["NAME"]; name != "" {
+				if version := release["VERSION"]; version != "" {
+					return name + " " + version, nil
 				}
+				return name, nil
 			}
+		}
 
-			if out, err := exec.Command("lsb_release", "-d").Output(); err == nil {
-				if parts := strings.SplitN(string(out), ":\t", 2); len(parts) > 1 {
-					cachedOSInfo = strings.TrimSpace(parts[1])
-					return
-				}
+		if out, err := sys.Output(ctx, "lsb_release", "-d"); err == nil {
+			if parts := strings.SplitN(string(out), ":\t", 2); len(parts) > 1 {
+				return strings.TrimSpace(parts[1]), nil
 			}
 		}
-		cachedOSInfo = runtime.GOOS
-	})
-	return cachedOSInfo
+	}
+	return runtime.GOOS, nil
 }
@@ -0,0 +1,85 @@
+package osinfo
+
+import (
+	"asf/system"
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGetOSInfoVariants(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  string
+	}{
+		{
+			name: "arch",
+			files: fstest.MapFS{"etc/os-release": {Data: []byte(`NAME="Arch Linux"
+PRETTY_NAME="Arch Linux"
+ID=arch
+BUILD_ID=rolling
+`)}},
+			want: "Arch Linux",
+		},
+		{
+			name: "unquoted pretty name",
+			files: fstest.MapFS{"etc/os-release": {Data: []byte(`NAME=Gentoo
+ID=gentoo
+PRETTY_NAME=Gentoo
+`)}},
+			want: "Gentoo",
+		},
+		{
+			name:  "single quotes",
+			files: fstest.MapFS{"etc/os-release": {Data: []byte("PRETTY_NAME='Void Linux'\nID=void\n")}},
+			want:  "Void Linux",
+		},
+		{
+			name:  "escaped quotes",
+			files: fstest.MapFS{"etc/os-release": {Data: []byte(`PRETTY_NAME="Test \"Edition\" 1.0"` + "\n")}},
+			want:  `Test "Edition" 1.0`,
+		},
+		{
+			name: "name and version only",
+			files: fstest.MapFS{"etc/os-release": {Data: []byte(`# komentarz
+NAME="Alpine Linux"
+VERSION="3.19.1"
+`)}},
+			want: "Alpine Linux 3.19.1",
+		},
+		{
+			name: "usr lib fallback",
+			files: fstest.MapFS{"usr/lib/os-release": {Data: []byte(`PRETTY_NAME="NixOS 24.05 (Uakari)"
+ID=nixos
+`)}},
+			want: "NixOS 24.05 (Uakari)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetOSInfo(context.Background(), system.FromFS(tt.files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("GetOSInfo() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetOSInfoLsbRelease(t *testing.T) {
+	sys := system.FromFS(fstest.MapFS{}).WithRunner(system.StaticRunner{
+		"lsb_release -d": "Description:\tUbuntu 14.04.6 LTS\n",
+	})
+
+	got, err := GetOSInfo(context.Background(), sys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Ubuntu 14.04.6 LTS" {
+		t.Errorf("GetOSInfo() = %q", got)
+	}
+}
@@ -1,36 +1,55 @@
 package osinfo
 
 import (
-	"io/ioutil"
-	"os"
-	"os/exec"
-	"strconv"
-	"strings"
-	"sync"
+	"asf/modules"
+	"asf/system"
+	"bytes"
+	"context"
+	"fmt"
 )
 
-var (
-	cachedPkgCount string
-	pkgCountOnce   sync.Once
-)
+func GetPackageCount(ctx context.Context, sys *system.System) (int, error) {
+	if sys.Exists("/var/lib/pacman/local") {
+		entries, err := sys.ReadDir("/var/lib/pacman/local")
+		if err != nil {
+			return 0, err
+		}
+		// Każdy pakiet to katalog; obok leży jeszcze plik ALPM_DB_VERSION.
+		count := 0
+		for _, entry := range entries {
+			if entry.IsDir() {
+				count++
+			}
+		}
+		return count, nil
+	}
 
-func GetPackageCount() string {
-	pkgCountOnce.Do(func() {
-		var count string
+	var name string
+	var args []string
+	if sys.Exists("/var/lib/dpkg/status") {
+		name, args = "dpkg-query", []string{"-f", ".\n", "-W"}
+	} else if sys.Exists("/var/lib/rpm") {
+		name, args = "rpm", []string{"-qa"}
+	} else {
+		return 0, modules.ErrUnavailable
+	}
 
-		if _, err := os.Stat("/var/lib/pacman/local"); err == nil {
-			files, _ := ioutil.ReadDir("/var/lib/pacman/local")
-			count = strconv.Itoa(len(files))
-		} else if _, err := os.Stat("/var/lib/dpkg/status"); err == nil {
-			out, _ := exec.Command("dpkg-query", "-f", ".\n", "-W").Output()
-			count = strconv.Itoa(len(strings.Split(string(out), "\n")) - 1)
-		} else if _, err := os.Stat("/var/lib/rpm"); err == nil {
-			out, _ := exec.Command("rpm", "-qa").Output()
-			count = strconv.Itoa(len(strings.Split(string(out), "\n")) - 1)
-		} else {
-			count = "Unknown"
-		}
-		cachedPkgCount = count
-	})
-	return cachedPkgCount
+	out, err := sys.Output(ctx, name, args...)
+	if err != nil && name == "dpkg-query" {
+		return countDpkgStatus(sys)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("nie udało się uruchomić %s: %w", name, err)
+	}
+	return bytes.Count(out, []byte("\n")), nil
+}
+
+// countDpkgStatus liczy zainstalowane pakiety bezpośrednio z bazy dpkg, gdy dpkg-query
+// nie jest dostępne (np. przy --sysroot wskazującym na obraz innego systemu).
+func countDpkgStatus(sys *system.System) (int, error) {
+	data, err := sys.ReadFile("/var/lib/dpkg/status")
+	if err != nil {
+		return 0, err
+	}
+	return bytes.Count(data, []byte("\nStatus: install ok installed")), nil
 }
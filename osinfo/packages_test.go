@@ -0,0 +1,68 @@
+package osinfo
+
+import (
+	"asf/system"
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGetPackageCountPacman(t *testing.T) {
+	fsys := fstest.MapFS{
+		"var/lib/pacman/local/ALPM_DB_VERSION":   {Data: []byte("9\n")},
+		"var/lib/pacman/local/bash-5.2.026-2":    {Mode: fs.ModeDir | 0755},
+		"var/lib/pacman/local/glibc-2.39-1":      {Mode: fs.ModeDir | 0755},
+		"var/lib/pacman/local/linux-6.8.1.arch1": {Mode: fs.ModeDir | 0755},
+	}
+
+	got, err := GetPackageCount(context.Background(), system.FromFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 3 {
+		t.Errorf("GetPackageCount() = %d, want 3", got)
+	}
+}
+
+func TestGetPackageCountDpkgWithoutCommands(t *testing.T) {
+	fsys := fstest.MapFS{
+		"var/lib/dpkg/status": {Data: []byte(`Package: bash
+Status: install ok installed
+Version: 5.2.15-2
+
+Package: old-lib
+Status: deinstall ok config-files
+Version: 1.0
+
+Package: coreutils
+Status: install ok installed
+Version: 9.1-1
+`)},
+	}
+
+	got, err := GetPackageCount(context.Background(), system.FromFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 2 {
+		t.Errorf("GetPackageCount() = %d, want 2", got)
+	}
+}
+
+func TestGetPackageCountRpm(t *testing.T) {
+	fsys := fstest.MapFS{
+		"var/lib/rpm/rpmdb.sqlite": {Data: []byte{}},
+	}
+	sys := system.FromFS(fsys).WithRunner(system.StaticRunner{
+		"rpm -qa": "bash-5.2.26-3.fc40.x86_64\nglibc-2.39-2.fc40.x86_64\nkernel-6.8.5-301.fc40.x86_64\n",
+	})
+
+	got, err := GetPackageCount(context.Background(), sys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 3 {
+		t.Errorf("GetPackageCount() = %d, want 3", got)
+	}
+}
@@ -0,0 +1,235 @@
+package present
+
+import (
+	"asf/dodatki"
+	"asf/hardware"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Text zamienia dane zwrócone przez moduł na tekst wyświetlany w tabeli.
+func Text(data any) string {
+	switch v := data.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case time.Duration:
+		return FormatDuration(v)
+	case hardware.CPUInfo:
+		return CPU(v)
+	case hardware.Usage:
+		return Usage(v)
+	case []hardware.BatteryInfo:
+		return Batteries(v)
+	case []hardware.DiskInfo:
+		return Disks(v)
+	case []hardware.NetworkInterface:
+		return NetworkInterfaces(v)
+	case []hardware.DisplayInfo:
+		return Displays(v)
+	case []hardware.GPUDetails:
+		return GPUs(v)
+	case dodatki.UserHost:
+		if v.User == "" {
+			return v.Host
+		}
+		return v.User + "@" + v.Host
+	case dodatki.Shell:
+		return strings.TrimSpace(v.Name + " " + v.Version)
+	case dodatki.Terminal:
+		return v.Name
+	case dodatki.TerminalFont:
+		return TerminalFont(v)
+	case dodatki.Track:
+		return Track(v)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// JSONValue zwraca dane w postaci przyjaznej dla JSON. Większość typów ma już tagi json
+// i jest zwracana bez zmian; czas trwania zamieniany jest na sekundy.
+func JSONValue(data any) any {
+	switch v := data.(type) {
+	case time.Duration:
+		return struct {
+			Seconds int64 `json:"seconds"`
+		}{int64(v / time.Second)}
+	default:
+		return v
+	}
+}
+
+func FormatDuration(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%d dni", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%d godz.", hours))
+	}
+	if minutes > 0 || len(parts) == 0 { // Upewnij się, że "0 min" jest wyświetlane, jeśli czas jest krótszy niż godzina
+		parts = append(parts, fmt.Sprintf("%d min", minutes))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func GiB(bytes uint64) float64 {
+	return float64(bytes) / 1024 / 1024 / 1024
+}
+
+func Usage(u hardware.Usage) string {
+	return fmt.Sprintf("%.1fGB / %.1fGB (%.1f%%)", GiB(u.UsedBytes), GiB(u.TotalBytes), u.Percent)
+}
+
+func CPU(info hardware.CPUInfo) string {
+	var cpuDetails []string
+	if info.Model != "" {
+		cpuDetails = append(cpuDetails, info.Model)
+	} else {
+		cpuDetails = append(cpuDetails, "Nieznany CPU")
+	}
+
+	if info.Cores > 0 {
+		coreInfo := fmt.Sprintf("%dC", info.Cores)
+		if info.Threads > 0 && info.Threads != info.Cores {
+			coreInfo += fmt.Sprintf("/%dT", info.Threads)
+		}
+		cpuDetails = append(cpuDetails, coreInfo)
+	}
+
+	if info.MaxFreqHz > 0 {
+		cpuDetails = append(cpuDetails, fmt.Sprintf("%.2fGHz", float64(info.MaxFreqHz)/1_000_000_000))
+	}
+
+	return strings.Join(cpuDetails, ", ")
+}
+
+func Batteries(batteries []hardware.BatteryInfo) string {
+	parts := make([]string, 0, len(batteries))
+	for _, b := range batteries {
+		parts = append(parts, fmt.Sprintf("%d%% (%s)", b.Capacity, b.Status))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Disk zwraca zajętość dysku w stylu linii RAM, poprzedzoną punktem montowania.
+func Disk(d hardware.DiskInfo) string {
+	name := d.Mountpoint
+	if d.FSType != "" {
+		name += " (" + d.FSType + ")"
+	}
+	return name + ": " + Usage(d.Usage)
+}
+
+// Disks zwraca jeden punkt montowania na linię, żeby długa lista nie była ucinana w jednym wierszu.
+func Disks(disks []hardware.DiskInfo) string {
+	lines := make([]string, 0, len(disks))
+	for _, d := range disks {
+		lines = append(lines, Disk(d))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NetworkInterface zwraca nazwę interfejsu z rodzajem łącza i adresami, np.
+// "wlan0 (Wi-Fi): 192.168.1.5/24", albo sam stan, gdy interfejs nie działa.
+func NetworkInterface(iface hardware.NetworkInterface) string {
+	name := iface.Name
+	switch {
+	case iface.Wireless:
+		name += " (Wi-Fi)"
+	case iface.SpeedMbps >= 1000 && iface.SpeedMbps%1000 == 0:
+		name += fmt.Sprintf(" (%d Gb/s)", iface.SpeedMbps/1000)
+	case iface.SpeedMbps > 0:
+		name += fmt.Sprintf(" (%d Mb/s)", iface.SpeedMbps)
+	}
+	if !iface.Up() && iface.State != "unknown" {
+		return name + ": " + iface.State
+	}
+	if len(iface.Addresses) == 0 {
+		return name + ": brak adresu"
+	}
+	return name + ": " + strings.Join(iface.Addresses, ", ")
+}
+
+// NetworkInterfaces zwraca jeden interfejs na linię, tak jak Displays.
+func NetworkInterfaces(ifaces []hardware.NetworkInterface) string {
+	lines := make([]string, 0, len(ifaces))
+	for _, iface := range ifaces {
+		lines = append(lines, NetworkInterface(iface))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Display zwraca monitor w postaci "Dell U2720Q (DP-1): 3840x2160 @ 60 Hz".
+func Display(d hardware.DisplayInfo) string {
+	name := strings.TrimSpace(d.Manufacturer + " " + d.Model)
+	if name == "" {
+		name = d.Connector
+	} else {
+		name += " (" + d.Connector + ")"
+	}
+	if d.Width == 0 {
+		return name
+	}
+	res := fmt.Sprintf("%dx%d", d.Width, d.Height)
+	if d.RefreshHz > 0 {
+		res += " @ " + strconv.FormatFloat(d.RefreshHz, 'f', -1, 64) + " Hz"
+	}
+	return name + ": " + res
+}
+
+// Displays zwraca jeden monitor na linię; tabela pokazuje każdą linię w osobnym wierszu.
+func Displays(displays []hardware.DisplayInfo) string {
+	lines := make([]string, 0, len(displays))
+	for _, d := range displays {
+		lines = append(lines, Display(d))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func GPU(gpu hardware.GPUDetails) string {
+	if gpu.Model != "" {
+		return gpu.Model
+	}
+	if gpu.SubsystemID != "" {
+		return fmt.Sprintf("%s (ID: %s SubID: %s)", gpu.Vendor, gpu.PCI_ID, gpu.SubsystemID)
+	}
+	return fmt.Sprintf("%s (ID: %s)", gpu.Vendor, gpu.PCI_ID)
+}
+
+func GPUs(gpus []hardware.GPUDetails) string {
+	parts := make([]string, 0, len(gpus))
+	for _, gpu := range gpus {
+		parts = append(parts, GPU(gpu))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func TerminalFont(f dodatki.TerminalFont) string {
+	if f.Size == 0 {
+		return f.Family
+	}
+	return fmt.Sprintf("%s (%spt)", f.Family, strconv.FormatFloat(f.Size, 'f', -1, 64))
+}
+
+func Track(t dodatki.Track) string {
+	if t.Artist == "" {
+		return t.Title
+	}
+	if t.Title == "" {
+		return t.Artist
+	}
+	return t.Artist + " - " + t.Title
+}
@@ -0,0 +1,55 @@
+package present
+
+import (
+	"asf/dodatki"
+	"asf/hardware"
+	"testing"
+	"time"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+		want string
+	}{
+		{"string", "Arch Linux", "Arch Linux"},
+		{"int", 1234, "1234"},
+		{"uptime minutes", 42 * time.Minute, "42 min"},
+		{"uptime zero", 30 * time.Second, "0 min"},
+		{"uptime days", 3*24*time.Hour + 5*time.Hour, "3 dni, 5 godz."},
+		{"cpu", hardware.CPUInfo{Model: "AMD Ryzen 7 5800X 8-Core Processor", Cores: 8, Threads: 16, MaxFreqHz: 4_850_000_000}, "AMD Ryzen 7 5800X 8-Core Processor, 8C/16T, 4.85GHz"},
+		{"cpu unknown", hardware.CPUInfo{Threads: 1}, "Nieznany CPU"},
+		{"usage", hardware.NewUsage(2*1024*1024*1024, 8*1024*1024*1024), "2.0GB / 8.0GB (25.0%)"},
+		{"batteries", []hardware.BatteryInfo{{Name: "BAT0", Capacity: 87, Status: "Charging"}, {Name: "BAT1", Capacity: 100, Status: "Full"}}, "87% (Charging), 100% (Full)"},
+		{"disks", []hardware.DiskInfo{{Mountpoint: "/", FSType: "ext4", Usage: hardware.NewUsage(30*1024*1024*1024, 120*1024*1024*1024)}, {Mountpoint: "/home", Usage: hardware.NewUsage(512*1024*1024, 2*1024*1024*1024)}}, "/ (ext4): 30.0GB / 120.0GB (25.0%)\n/home: 0.5GB / 2.0GB (25.0%)"},
+		{"network", []hardware.NetworkInterface{
+			{Name: "enp3s0", State: "up", SpeedMbps: 1000, Addresses: []string{"192.168.1.10/24", "fe80::1/64"}},
+			{Name: "wlan0", State: "dormant", Wireless: true},
+			{Name: "wg0", State: "unknown", Addresses: []string{"10.0.0.2/32"}},
+		}, "enp3s0 (1 Gb/s): 192.168.1.10/24, fe80::1/64\nwlan0 (Wi-Fi): dormant\nwg0: 10.0.0.2/32"},
+		// Te same monitory, które hardware.GetDisplayInfo odczytuje w display_test z testdata/edid.
+		{"displays", []hardware.DisplayInfo{
+			{Connector: "DP-1", Manufacturer: "Dell", Model: "U2720Q", Width: 3840, Height: 2160, RefreshHz: 60},
+			{Connector: "eDP-1", Manufacturer: "BOE", Width: 2256, Height: 1504, RefreshHz: 58.04},
+			{Connector: "Virtual-1", Width: 1280, Height: 800},
+		}, "Dell U2720Q (DP-1): 3840x2160 @ 60 Hz\nBOE (eDP-1): 2256x1504 @ 58.04 Hz\nVirtual-1: 1280x800"},
+		{"gpu without model", []hardware.GPUDetails{{Vendor: "NVIDIA", PCI_ID: "10de:25a0", SubsystemID: "1043:13a4"}}, "NVIDIA (ID: 10de:25a0 SubID: 1043:13a4)"},
+		{"user", dodatki.UserHost{User: "lis", Host: "nora"}, "lis@nora"},
+		{"user unknown under sysroot", dodatki.UserHost{Host: "nora"}, "nora"},
+		{"shell", dodatki.Shell{Name: "zsh", Version: "5.9"}, "zsh 5.9"},
+		{"shell without version", dodatki.Shell{Name: "dash"}, "dash"},
+		{"terminal", dodatki.Terminal{Name: "GNOME Terminal", Process: "gnome-terminal-"}, "GNOME Terminal"},
+		{"terminal font", dodatki.TerminalFont{Family: "JetBrains Mono", Size: 11.5}, "JetBrains Mono (11.5pt)"},
+		{"terminal font default size", dodatki.TerminalFont{Family: "monospace"}, "monospace"},
+		{"track", dodatki.Track{Artist: "Kult", Title: "Arahja"}, "Kult - Arahja"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.data); got != tt.want {
+				t.Errorf("Text() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
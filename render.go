@@ -0,0 +1,160 @@
+package main
+
+import (
+	"asf/config"
+	"asf/fetch"
+	"asf/modules"
+	"asf/term"
+	"asf/theme"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type lineKind int
+
+const (
+	lineInfo lineKind = iota
+	lineSeparator
+	lineBreak
+)
+
+type infoPair struct {
+	Label string
+	Value string
+	// Icon jest wypisywana przed etykietą.
+	Icon string
+	// Color to sekwencja ANSI nadpisująca kolor etykiety; pusta oznacza kolor z motywu.
+	Color string
+	// Title oznacza linię user@host, której wartość dostaje kolor tytułu z motywu.
+	Title bool
+	Kind  lineKind
+}
+
+// infoPairsFromReport układa linie tabeli według wpisów z konfiguracji, nakładając nadpisane etykiety,
+// ikony i kolory. Błędy modułów (poza brakiem danych) trafiają do errOut, raz na moduł.
+func infoPairsFromReport(layout []config.ModuleEntry, report fetch.Report, errOut io.Writer) []infoPair {
+	byName := make(map[string]fetch.Entry, len(report.Entries))
+	for _, entry := range report.Entries {
+		byName[entry.Name] = entry
+		if entry.Err != nil && !entry.TimedOut() && !errors.Is(entry.Err, modules.ErrUnavailable) {
+			fmt.Fprintf(errOut, "Błąd modułu %s: %v\n", entry.Name, entry.Err)
+		}
+	}
+
+	infoPairs := []infoPair{}
+	for _, item := range layout {
+		switch item.Name {
+		case config.ModuleSeparator:
+			infoPairs = append(infoPairs, infoPair{Kind: lineSeparator})
+			continue
+		case config.ModuleBreak:
+			infoPairs = append(infoPairs, infoPair{Kind: lineBreak})
+			continue
+		}
+
+		entry, ok := byName[item.Name]
+		if !ok {
+			continue
+		}
+		pair := infoPair{Label: entry.Label, Icon: item.Icon, Title: item.Name == "user"}
+		if item.Label != "" {
+			pair.Label = item.Label
+		}
+		if item.Color != "" {
+			color, err := theme.ParseColor(item.Color)
+			if err != nil {
+				fmt.Fprintf(errOut, "Moduł %s: %v, używam koloru domyślnego.\n", item.Name, err)
+			}
+			pair.Color = color
+		}
+
+		switch {
+		case entry.TimedOut():
+			pair.Value = "przekroczono czas"
+		case entry.Err != nil:
+			continue
+		default:
+			// Moduły z wieloma elementami (np. monitory) zwracają po jednym w linii;
+			// każda linia dostaje własny wiersz z tą samą etykietą.
+			for _, line := range strings.Split(entry.Text(), "\n") {
+				pair.Value = line
+				infoPairs = append(infoPairs, pair)
+			}
+			continue
+		}
+		infoPairs = append(infoPairs, pair)
+	}
+	return infoPairs
+}
+
+// title zwraca etykietę razem z ikoną.
+func (p infoPair) title() string {
+	if p.Icon == "" {
+		return p.Label
+	}
+	return p.Icon + " " + p.Label
+}
+
+// buildInfoLines składa linie tabeli. valueWidth > 0 ogranicza szerokość wartości: dłuższe są
+// obcinane wielokropkiem albo, gdy wrap, zawijane w kolejnych liniach pod separatorem.
+func buildInfoLines(infoPairs []infoPair, maxLabelLen, valueWidth int, wrap bool, pal theme.Palette) []string {
+	reset := pal.Reset
+
+	maxValueLen := 0
+	for _, pair := range infoPairs {
+		if pair.Kind == lineInfo {
+			maxValueLen = max(maxValueLen, term.Width(pair.Value))
+		}
+	}
+	if valueWidth > 0 {
+		maxValueLen = min(maxValueLen, valueWidth)
+	}
+
+	var infoLines []string
+	i := 0
+	for _, pair := range infoPairs {
+		switch pair.Kind {
+		case lineSeparator:
+			infoLines = append(infoLines, fmt.Sprintf("%s%s%s", pal.Separator, strings.Repeat("─", maxLabelLen+3+maxValueLen), reset))
+			continue
+		case lineBreak:
+			infoLines = append(infoLines, "")
+			continue
+		}
+
+		labelColor, sepColor, valueColor := pal.Label, pal.Separator, pal.Value
+		if i%2 == 1 {
+			labelColor, sepColor, valueColor = pal.LabelAlt, pal.SeparatorAlt, pal.ValueAlt
+		}
+		i++
+		if pair.Color != "" && pal.Enabled() {
+			labelColor = pair.Color
+		}
+		if pair.Title && pal.Title != "" {
+			valueColor = pal.Title
+		}
+
+		values := []string{pair.Value}
+		if valueWidth > 0 {
+			if wrap {
+				values = term.Wrap(pair.Value, valueWidth)
+			} else {
+				values[0] = term.Truncate(pair.Value, valueWidth)
+			}
+		}
+
+		title := pair.title()
+		separator := fmt.Sprintf("%s│%s", sepColor, reset)
+		for n, v := range values {
+			if n > 0 {
+				title = ""
+			}
+			alignedLabel := fmt.Sprintf("%s%s%s %s", labelColor, title, strings.Repeat(" ", maxLabelLen-term.Width(title)), reset)
+			value := fmt.Sprintf("%s%s%s", valueColor, v, reset)
+			infoLines = append(infoLines, fmt.Sprintf("%s%s %s", alignedLabel, separator, value))
+		}
+	}
+	return infoLines
+}
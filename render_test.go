@@ -0,0 +1,384 @@
+package main
+
+import (
+	"asf/config"
+	"asf/dodatki"
+	"asf/fetch"
+	"asf/graphics"
+	"asf/hardware"
+	"asf/logo"
+	"asf/modules"
+	"asf/system"
+	"asf/term"
+	"asf/theme"
+	"bytes"
+	"context"
+	"flag"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "nadpisz pliki w testdata/golden aktualnym wyjściem")
+
+func readLogo(t *testing.T, name string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "logos", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(string(data), "\n")
+}
+
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (uruchom go test -update, żeby utworzyć plik)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("wyjście różni się od %s (go test -update, żeby zaktualizować)\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
+
+var samplePairs = []infoPair{
+	{Label: "User", Value: "lis@nora"},
+	{Label: "OS", Value: "Arch Linux"},
+	{Label: "Kernel", Value: "6.8.1-arch1-1"},
+	{Label: "Packages", Value: "1234"},
+	{Label: "WM", Value: "Hyprland"},
+	{Label: "Uptime", Value: "1 dni, 2 godz., 3 min"},
+	{Label: "CPU", Value: "AMD Ryzen 7 5800X 8-Core Processor, 8C/16T, 4.85GHz"},
+	{Label: "RAM", Value: "7.8GB / 31.3GB (25.0%)"},
+}
+
+func markupLogo(t *testing.T, name string, color bool) []string {
+	t.Helper()
+	l, err := logo.Load(filepath.Join("testdata", "logos", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l.Render(palette(t, theme.DefaultName).Logo, color)
+}
+
+func palette(t *testing.T, name string) theme.Palette {
+	t.Helper()
+	th, err := theme.Bundled(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pal, err := th.Palette()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pal
+}
+
+var titlePairs = []infoPair{
+	{Label: "User", Value: "lis@nora", Title: true},
+	{Label: "OS", Value: "Arch Linux"},
+	{Label: "Kernel", Value: "6.8.1-arch1-1"},
+	{Kind: lineSeparator},
+}
+
+var layoutPairs = []infoPair{
+	{Label: "User", Value: "lis@nora"},
+	{Kind: lineSeparator},
+	{Label: "OS", Value: "Arch Linux", Icon: "◆"},
+	{Label: "Music", Value: "Kult - Arahja", Icon: "♪", Color: "\033[35m"},
+	{Kind: lineBreak},
+	{Label: "RAM", Value: "7.8GB / 31.3GB (25.0%)"},
+	{Kind: lineSeparator},
+}
+
+func TestRenderGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs []infoPair
+		logo  []string
+		pal   theme.Palette
+	}{
+		{"fox", samplePairs, readLogo(t, "fox.txt"), palette(t, theme.DefaultName)},
+		{"tux", samplePairs, readLogo(t, "tux.txt"), palette(t, theme.DefaultName)},
+		{"no_logo", samplePairs, nil, palette(t, theme.DefaultName)},
+		{"empty", nil, nil, palette(t, theme.DefaultName)},
+		{"no_color", samplePairs, readLogo(t, "tux.txt"), theme.Palette{}},
+		{"layout", layoutPairs, readLogo(t, "tux.txt"), palette(t, theme.DefaultName)},
+		{"mixed_width", samplePairs, readLogo(t, "mixed.txt"), palette(t, theme.DefaultName)},
+		{"logo_markup", samplePairs, markupLogo(t, "fox_markup.txt", true), palette(t, theme.DefaultName)},
+		{"logo_markup_no_color", samplePairs, markupLogo(t, "fox_markup.txt", false), theme.Palette{}},
+		{"theme_catppuccin", titlePairs, readLogo(t, "tux.txt"), palette(t, "catppuccin")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			renderText(&buf, tt.pairs, tt.logo, tt.pal, defaultLayout)
+			checkGolden(t, tt.name, buf.Bytes())
+		})
+	}
+}
+
+func TestRenderWidthGolden(t *testing.T) {
+	pairs := append(slices.Clone(samplePairs),
+		infoPair{Label: "GPU", Value: "AMD Radeon RX 6800/6800 XT / 6900 XT (Navi 21), Intel UHD Graphics 630"},
+		infoPair{Kind: lineSeparator},
+	)
+	tests := []struct {
+		name  string
+		width int
+		wrap  bool
+	}{
+		{"width60_truncate", 60, false},
+		{"width60_wrap", 60, true},
+		{"width40_stacked", 40, false},
+		{"width10_no_logo", 10, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := defaultLayout
+			opts.Width, opts.Wrap = tt.width, tt.wrap
+			renderText(&buf, pairs, readLogo(t, "tux.txt"), theme.Palette{}, opts)
+			for i, line := range strings.Split(buf.String(), "\n") {
+				if w := term.Width(line); w > tt.width {
+					t.Errorf("linia %d ma szerokość %d > %d: %q", i, w, tt.width, line)
+				}
+			}
+			checkGolden(t, tt.name, buf.Bytes())
+		})
+	}
+}
+
+// Moduły z listą elementów muszą dawać po jednym wierszu na element, żeby przy wąskim terminalu
+// ucinany był najwyżej jeden element, a nie cała lista.
+func TestRenderListsGolden(t *testing.T) {
+	const gb = 1024 * 1024 * 1024
+	tests := []struct {
+		name  string
+		entry fetch.Entry
+	}{
+		{"disks_width60", fetch.Entry{Name: "disk", Label: "Disk", Data: []hardware.DiskInfo{
+			{Mountpoint: "/", FSType: "ext4", Usage: hardware.NewUsage(30*gb, 120*gb)},
+			{Mountpoint: "/home", FSType: "btrfs", Usage: hardware.NewUsage(700*gb, 1800*gb)},
+			{Mountpoint: "/run/media/lis/Kopia zapasowa", FSType: "exfat", Usage: hardware.NewUsage(3*gb/2, 60*gb)},
+		}}},
+		{"network_width60", fetch.Entry{Name: "network", Label: "Network", Data: []hardware.NetworkInterface{
+			{Name: "enp3s0", State: "up", SpeedMbps: 1000, Addresses: []string{"192.168.1.10/24", "fe80::aaa1:59ff:fe12:3456/64"}},
+			{Name: "wlan0", State: "dormant", Wireless: true},
+			{Name: "wg0", State: "unknown", Addresses: []string{"10.0.0.2/32"}},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := fetch.Report{Entries: []fetch.Entry{{Name: "os", Label: "OS", Data: "Arch Linux"}, tt.entry}}
+			var stderr bytes.Buffer
+			pairs := infoPairsFromReport(config.Entries("os", tt.entry.Name), report, &stderr)
+
+			opts := defaultLayout
+			opts.Width = 60
+			var buf bytes.Buffer
+			renderText(&buf, pairs, readLogo(t, "tux.txt"), theme.Palette{}, opts)
+			for i, line := range strings.Split(buf.String(), "\n") {
+				if w := term.Width(line); w > opts.Width {
+					t.Errorf("linia %d ma szerokość %d > %d: %q", i, w, opts.Width, line)
+				}
+			}
+			checkGolden(t, tt.name, buf.Bytes())
+		})
+	}
+}
+
+func TestRenderLayoutsGolden(t *testing.T) {
+	rounded, double, heavy := boxStyles["rounded"], boxStyles["double"], boxStyles["heavy"]
+	tests := []struct {
+		name   string
+		modify func(*layoutOptions)
+		color  bool
+		logo   string
+	}{
+		{"layout_top", func(o *layoutOptions) { o.Logo = logoTop }, false, "tux.txt"},
+		{"layout_right", func(o *layoutOptions) { o.Logo = logoRight }, false, "tux.txt"},
+		{"layout_none", func(o *layoutOptions) { o.Logo = logoNone }, false, "tux.txt"},
+		{"layout_box_rounded", func(o *layoutOptions) { o.Box = &rounded }, true, "tux.txt"},
+		{"layout_box_double_right", func(o *layoutOptions) { o.Box = &double; o.Logo = logoRight }, false, "tux.txt"},
+		{"layout_box_heavy_top", func(o *layoutOptions) { o.Box = &heavy; o.Logo = logoTop }, false, "tux.txt"},
+		{"align_middle", func(o *layoutOptions) { o.Align = alignMiddle }, false, "fox.txt"},
+		{"align_bottom_right", func(o *layoutOptions) { o.Align = alignBottom; o.Logo = logoRight }, false, "fox.txt"},
+		{"layout_padding", func(o *layoutOptions) {
+			o.Gap, o.PaddingTop, o.PaddingBottom, o.PaddingLeft = 1, 0, 2, 3
+		}, false, "tux.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := defaultLayout
+			tt.modify(&opts)
+			pal := theme.Palette{}
+			if tt.color {
+				pal = palette(t, theme.DefaultName)
+			}
+			var buf bytes.Buffer
+			renderText(&buf, layoutPairs, readLogo(t, tt.logo), pal, opts)
+			checkGolden(t, tt.name, buf.Bytes())
+		})
+	}
+}
+
+func TestLayoutFromConfig(t *testing.T) {
+	two, zero, negative := 2, 0, -1
+
+	opts, err := layoutFromConfig(config.Config{})
+	if err != nil || !reflect.DeepEqual(opts, defaultLayout) {
+		t.Errorf("pusta konfiguracja: %+v, %v; want %+v", opts, err, defaultLayout)
+	}
+
+	opts, err = layoutFromConfig(config.Config{
+		Layout: config.LayoutRight, Box: "double", Overflow: config.OverflowWrap, Align: config.AlignMiddle,
+		Gap: &two, PaddingTop: &zero, PaddingLeft: &two,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	double := boxStyles["double"]
+	want := layoutOptions{Logo: logoRight, Align: alignMiddle, Box: &double, Wrap: true, Gap: 2, PaddingTop: 0, PaddingBottom: 1, PaddingLeft: 2}
+	if !reflect.DeepEqual(opts, want) {
+		t.Errorf("layoutFromConfig = %+v, want %+v", opts, want)
+	}
+
+	for _, cfg := range []config.Config{{Layout: "bottom"}, {Align: "center"}, {Box: "ascii"}, {Gap: &negative}} {
+		if _, err := layoutFromConfig(cfg); err == nil {
+			t.Errorf("layoutFromConfig(%+v): oczekiwano błędu", cfg)
+		}
+	}
+}
+
+// Kolumna z informacjami musi zaczynać się w tym samym miejscu niezależnie od tego,
+// czy linia logo zawiera Braille, CJK, emoji czy sekwencje ANSI.
+func TestRenderAlignsInfoColumn(t *testing.T) {
+	var buf bytes.Buffer
+	renderText(&buf, samplePairs, readLogo(t, "mixed.txt"), theme.Palette{}, defaultLayout)
+
+	lines := strings.Split(buf.String(), "\n")
+	column := -1
+	for i, pair := range samplePairs {
+		line := lines[i+1]
+		idx := strings.Index(line, pair.Label+" ")
+		if idx < 0 {
+			t.Fatalf("linia %d bez etykiety %q: %q", i+1, pair.Label, line)
+		}
+		got := term.Width(line[:idx])
+		if column < 0 {
+			column = got
+		}
+		if got != column {
+			t.Errorf("linia %d: etykieta %q w kolumnie %d, chcę %d", i+1, pair.Label, got, column)
+		}
+	}
+}
+
+// Obrazek rysowany protokołem graficznym musi trafić w kolumnę logo także wtedy, gdy logo jest
+// po prawej, a całość ma wcięcie: rezerwacja wierszy nie może cofać kursora do kolumny 0.
+func TestRenderRightImageLogo(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, image.NewGray(image.Rect(0, 0, 16, 64))); err != nil {
+		t.Fatal(err)
+	}
+	img, err := graphics.Decode(encoded.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	logoLines, err := img.Logo(graphics.ITerm2, 2, 8, 16, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := layoutOptions{Logo: logoRight, Gap: 1, PaddingLeft: 2}
+	var buf bytes.Buffer
+	renderText(&buf, []infoPair{{Label: "OS", Value: "Arch"}}, logoLines, theme.Palette{}, opts)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("%d linii, want 4 (obrazek zajmuje 4 wiersze):\n%q", len(lines), buf.String())
+	}
+	want := "  OS │ Arch " + "\033D\033D\033D\033[3A" + "\0337\033]1337;File=inline=1;"
+	if !strings.HasPrefix(lines[0], want) {
+		t.Errorf("pierwsza linia = %q, want prefiks %q", lines[0], want)
+	}
+	if !strings.HasSuffix(lines[0], "\a\0338") {
+		t.Errorf("pierwsza linia = %q, want zakończenie przywróceniem kursora", lines[0])
+	}
+}
+
+func TestRenderSysrootGolden(t *testing.T) {
+	layout := config.Entries("user", "os", "kernel", "packages", "uptime", "battery", "cpu", "gpu", "ram", "swap", "music")
+	report, err := fetch.Collect(context.Background(), fetch.Options{
+		Modules: config.Config{Modules: layout}.ModuleNames(),
+		System:  system.FromFS(os.DirFS(filepath.Join("testdata", "sysroot"))),
+		Runner: system.StaticRunner{
+			"lspci -nn": "0b:00.0 VGA compatible controller [0300]: Advanced Micro Devices, Inc. [AMD/ATI] Navi 21 [Radeon RX 6800/6800 XT / 6900 XT] [1002:73bf] (rev c1)\n",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stderr bytes.Buffer
+	var buf bytes.Buffer
+	renderText(&buf, infoPairsFromReport(layout, report, &stderr), readLogo(t, "tux.txt"), palette(t, theme.DefaultName), defaultLayout)
+	if stderr.Len() > 0 {
+		t.Errorf("unexpected module errors:\n%s", stderr.String())
+	}
+	checkGolden(t, "sysroot_arch", buf.Bytes())
+}
+
+func TestInfoPairsLayout(t *testing.T) {
+	report := fetch.Report{Entries: []fetch.Entry{
+		{Name: "cpu", Label: "CPU", Data: "Ryzen"},
+		{Name: "music", Label: "Spotify", Data: dodatki.Track{Artist: "Kult", Title: "Arahja"}},
+		{Name: "swap", Label: "Swap", Err: modules.ErrUnavailable},
+		{Name: "gpu", Label: "GPU", Err: context.DeadlineExceeded},
+		{Name: "display", Label: "Display", Data: "BOE (eDP-1): 2256x1504\nDell U2720Q (DP-2): 3840x2160"},
+	}}
+	layout := []config.ModuleEntry{
+		{Name: "music", Label: "Music", Icon: "♪", Color: "magenta"},
+		{Name: config.ModuleSeparator},
+		{Name: "swap"},
+		{Name: "cpu"},
+		{Name: config.ModuleBreak},
+		{Name: "gpu", Color: "nie-kolor"},
+		{Name: "cpu", Label: "Procesor"},
+		{Name: "display"},
+	}
+
+	var stderr bytes.Buffer
+	got := infoPairsFromReport(layout, report, &stderr)
+	want := []infoPair{
+		{Label: "Music", Value: "Kult - Arahja", Icon: "♪", Color: "\033[35m"},
+		{Kind: lineSeparator},
+		{Label: "CPU", Value: "Ryzen"},
+		{Kind: lineBreak},
+		{Label: "GPU", Value: "przekroczono czas"},
+		{Label: "Procesor", Value: "Ryzen"},
+		{Label: "Display", Value: "BOE (eDP-1): 2256x1504"},
+		{Label: "Display", Value: "Dell U2720Q (DP-2): 3840x2160"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("infoPairsFromReport() =\n%+v\nwant\n%+v", got, want)
+	}
+	if !strings.Contains(stderr.String(), "nie-kolor") {
+		t.Errorf("brak ostrzeżenia o nieznanym kolorze: %q", stderr.String())
+	}
+}
@@ -0,0 +1,24 @@
+package system
+
+import (
+	"fmt"
+	"net"
+)
+
+// interfaceAddrs pyta jądro o adresy interfejsu hosta. Interfejs, którego nie ma (np. zniknął
+// między odczytem /sys/class/net a tym wywołaniem), nie ma adresów.
+func interfaceAddrs(name string) ([]string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, nil
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("nie udało się odczytać adresów %s: %w", name, err)
+	}
+	cidrs := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		cidrs = append(cidrs, addr.String())
+	}
+	return cidrs, nil
+}
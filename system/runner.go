@@ -0,0 +1,152 @@
+package system
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Runner uruchamia zewnętrzne programy (lspci, vulkaninfo, pgrep, playerctl, ...).
+// Sondy nigdy nie wołają os/exec bezpośrednio, tylko System.Output, który deleguje tutaj.
+type Runner interface {
+	Run(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// waitDelay to czas, przez jaki po anulowaniu czekamy na zamknięcie wyjścia przez procesy potomne
+// (np. skrypt opakowujący, którego dziecko wciąż trzyma stdout), zanim przestaniemy na nie czekać.
+const waitDelay = 100 * time.Millisecond
+
+// ExecRunner uruchamia programy naprawdę, z anulowaniem przez kontekst. Po anulowaniu Run wraca
+// najpóźniej po waitDelay, więc długo działający program osadzający fetch nie gubi gorutyn.
+type ExecRunner struct{}
+
+func (ExecRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = waitDelay
+	return cmd.Output()
+}
+
+// ExitError zastępuje *exec.ExitError przy odtwarzaniu nagranych poleceń.
+type ExitError struct {
+	Command  string
+	ExitCode int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("%s: exit status %d", e.Command, e.ExitCode)
+}
+
+// FixtureName zwraca bazową nazwę pliku, pod którą nagrywane jest dane wywołanie.
+// Zawiera czytelny fragment linii poleceń oraz skrót dokładnych argumentów, żeby uniknąć kolizji.
+func FixtureName(name string, args ...string) string {
+	argv := append([]string{name}, args...)
+
+	var b strings.Builder
+	for _, r := range strings.Join(argv, "_") {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	readable := b.String()
+	if len(readable) > 60 {
+		readable = readable[:60]
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(argv, "\x00")))
+	return readable + "-" + hex.EncodeToString(sum[:4])
+}
+
+// Recorder uruchamia polecenia przez Runner i zapisuje ich wyjście do katalogu Dir,
+// w formacie czytanym później przez Replayer. Programy nieobecne w PATH nie są nagrywane,
+// bo Replayer i tak traktuje brak nagrania jak brak programu. Błąd zapisu nagrania
+// jest dołączany do błędu polecenia, żeby niepełne nagranie nie przeszło niezauważone.
+type Recorder struct {
+	Runner Runner
+	Dir    string
+}
+
+func (r Recorder) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, err := r.Runner.Run(ctx, name, args...)
+	if ctx.Err() != nil {
+		// Polecenie przerwane przez limit czasu nie jest reprezentatywne, nie nagrywamy go.
+		return out, err
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return out, err
+	}
+	if recErr := r.record(FixtureName(name, args...), out, exitErr); recErr != nil {
+		return out, errors.Join(err, fmt.Errorf("nie można nagrać wyjścia %s: %w", name, recErr))
+	}
+	return out, err
+}
+
+func (r Recorder) record(fixture string, out []byte, exitErr *exec.ExitError) error {
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return err
+	}
+	base := filepath.Join(r.Dir, fixture)
+	if err := os.WriteFile(base+".out", out, 0644); err != nil {
+		return err
+	}
+	if exitErr != nil {
+		return os.WriteFile(base+".exit", []byte(strconv.Itoa(exitErr.ExitCode())+"\n"), 0644)
+	}
+	return nil
+}
+
+// Replayer odtwarza polecenia nagrane przez Recorder. Polecenie bez nagrania
+// zachowuje się jak program nieobecny w PATH.
+type Replayer struct {
+	Dir string
+}
+
+func (r Replayer) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	base := filepath.Join(r.Dir, FixtureName(name, args...))
+	out, err := os.ReadFile(base + ".out")
+	if err != nil {
+		return nil, &exec.Error{Name: name, Err: exec.ErrNotFound}
+	}
+
+	if data, err := os.ReadFile(base + ".exit"); err == nil {
+		code, convErr := strconv.Atoi(strings.TrimSpace(string(data)))
+		if convErr != nil {
+			return nil, fmt.Errorf("niepoprawny plik %s.exit: %w", base, convErr)
+		}
+		if code != 0 {
+			return out, &ExitError{Command: name, ExitCode: code}
+		}
+	}
+	return out, nil
+}
+
+// StaticRunner zwraca z góry ustalone wyjście dla linii poleceń (program i argumenty
+// połączone spacjami). Polecenia spoza mapy zachowują się jak nieobecne w PATH.
+type StaticRunner map[string]string
+
+func (r StaticRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out, ok := r[strings.Join(append([]string{name}, args...), " ")]
+	if !ok {
+		return nil, &exec.Error{Name: name, Err: exec.ErrNotFound}
+	}
+	return []byte(out), nil
+}
@@ -0,0 +1,121 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	recorder := Recorder{
+		Runner: StaticRunner{"lspci -nn": "00:02.0 VGA compatible controller [0300]: Intel Corporation Device [8086:a7a0]\n"},
+		Dir:    dir,
+	}
+	if _, err := recorder.Run(ctx, "lspci", "-nn"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recorder.Run(ctx, "vulkaninfo"); !errors.Is(err, exec.ErrNotFound) {
+		t.Fatalf("err = %v, want exec.ErrNotFound", err)
+	}
+
+	replayer := Replayer{Dir: dir}
+	out, err := replayer.Run(ctx, "lspci", "-nn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "00:02.0 VGA compatible controller [0300]: Intel Corporation Device [8086:a7a0]\n" {
+		t.Errorf("replayed output = %q", out)
+	}
+
+	if _, err := replayer.Run(ctx, "vulkaninfo"); !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("err = %v, want exec.ErrNotFound", err)
+	}
+	if _, err := replayer.Run(ctx, "lspci"); !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("different arguments must not replay the same fixture, err = %v", err)
+	}
+}
+
+func TestFixtureNameDistinguishesArguments(t *testing.T) {
+	a := FixtureName("playerctl", "metadata", "--format", "{{artist}}\t{{title}}")
+	b := FixtureName("playerctl", "metadata", "--format", "{{artist}} {{title}}")
+	if a == b {
+		t.Errorf("FixtureName collision: %q", a)
+	}
+}
+
+func TestSystemWithoutRunner(t *testing.T) {
+	sys := New(t.TempDir())
+	if _, err := sys.Output(context.Background(), "uname", "-r"); !errors.Is(err, ErrNoCommands) {
+		t.Errorf("err = %v, want ErrNoCommands", err)
+	}
+}
+
+func TestRecorderReportsWriteErrors(t *testing.T) {
+	// Dir wskazuje na zwykły plik, więc nagranie nie może powstać.
+	dir := filepath.Join(t.TempDir(), "plik")
+	if err := os.WriteFile(dir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	recorder := Recorder{Runner: StaticRunner{"uname -r": "6.8.1\n"}, Dir: dir}
+
+	out, err := recorder.Run(context.Background(), "uname", "-r")
+	if err == nil {
+		t.Fatal("expected error when the fixture cannot be written")
+	}
+	if string(out) != "6.8.1\n" {
+		t.Errorf("output = %q, want the command output despite the recording error", out)
+	}
+}
+
+func TestRecorderSkipsMissingPrograms(t *testing.T) {
+	dir := t.TempDir()
+	recorder := Recorder{Runner: StaticRunner{}, Dir: dir}
+	if _, err := recorder.Run(context.Background(), "vulkaninfo"); !errors.Is(err, exec.ErrNotFound) {
+		t.Fatalf("err = %v, want exec.ErrNotFound", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("missing program left files in the recording: %v", entries)
+	}
+}
+
+func TestDiskSpace(t *testing.T) {
+	if _, err := FromFS(nil).DiskSpace("/"); !errors.Is(err, ErrNoStatfs) {
+		t.Errorf("FromFS: err = %v, want ErrNoStatfs", err)
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("statfs jest obsługiwany tylko na Linuksie")
+	}
+	space, err := New(t.TempDir()).DiskSpace("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if space.Total == 0 || space.Avail > space.Free || space.Free > space.Total {
+		t.Errorf("DiskSpace() = %+v, want Avail <= Free <= Total > 0", space)
+	}
+}
+
+func TestExecRunnerReturnsWhenChildHoldsOutput(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("brak sh")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// sleep dziedziczy stdout, więc bez WaitDelay Output czekałby na nie całe 10 sekund.
+	start := time.Now()
+	_, err := ExecRunner{}.Run(ctx, "sh", "-c", "sleep 10 & echo x")
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Run wrócił po %v, want < 2s", elapsed)
+	}
+	if err == nil {
+		t.Error("expected error after the deadline")
+	}
+}
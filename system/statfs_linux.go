@@ -0,0 +1,12 @@
+package system
+
+import "syscall"
+
+func statfs(path string) (DiskSpace, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return DiskSpace{}, err
+	}
+	bsize := uint64(st.Bsize)
+	return DiskSpace{Total: st.Blocks * bsize, Free: st.Bfree * bsize, Avail: st.Bavail * bsize}, nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package system
+
+// statfs poza Linuksem nie jest obsługiwany.
+func statfs(path string) (DiskSpace, error) {
+	return DiskSpace{}, ErrNoStatfs
+}
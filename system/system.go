@@ -0,0 +1,178 @@
+// Package system daje sondom dostęp do systemu plików badanej maszyny. Wszystkie ścieżki
+// (/proc, /sys, /etc, /var) są rozwiązywane względem korzenia, więc te same sondy działają
+// na żywym systemie, zamontowanym obrazie dysku, chroocie albo drzewie testowym.
+package system
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoCommands jest zwracany przez Output, gdy badany system nie jest systemem, na którym działa asfetch,
+// więc uruchamianie jego programów nie miałoby sensu.
+var ErrNoCommands = errors.New("zewnętrzne polecenia są niedostępne dla tego korzenia")
+
+// ErrNoStatfs jest zwracany przez DiskSpace, gdy system nie leży na prawdziwym systemie plików.
+var ErrNoStatfs = errors.New("pojemność systemów plików jest niedostępna dla tego korzenia")
+
+type System struct {
+	FS fs.FS
+	// Runner uruchamia zewnętrzne programy; nil oznacza, że polecenia są niedostępne.
+	Runner Runner
+	// Env odczytuje zmienne środowiskowe badanej sesji; nil oznacza, że środowisko jest nieznane
+	// (np. zamontowany obraz, którego zmienne nie są zmiennymi procesu asfetch).
+	Env func(key string) string
+	// Statfs zwraca pojemność systemu plików, w którym leży ścieżka (już z korzeniem);
+	// nil oznacza, że pojemności nie da się sprawdzić (np. system.FromFS).
+	Statfs func(path string) (DiskSpace, error)
+	// Addrs zwraca adresy interfejsu sieciowego w notacji CIDR; nil oznacza, że adresy są nieznane,
+	// bo jądro zna tylko interfejsy systemu, na którym działa asfetch.
+	Addrs func(iface string) ([]string, error)
+	// CacheDir to katalog, w którym sondy mogą trzymać wyniki między uruchomieniami (np. wersje
+	// powłok); pusty oznacza, że nic nie jest zapisywane na dysk.
+	CacheDir string
+	root     string
+}
+
+// DiskSpace to pojemność systemu plików w bajtach, tak jak podaje ją statfs(2).
+type DiskSpace struct {
+	Total uint64
+	// Free to wolne miejsce razem z blokami zarezerwowanymi dla roota.
+	Free uint64
+	// Avail to miejsce dostępne dla zwykłego użytkownika (to, co df pokazuje jako Avail).
+	Avail uint64
+}
+
+// Host zwraca system, na którym działa asfetch.
+func Host() *System {
+	return &System{FS: os.DirFS("/"), Runner: ExecRunner{}, Env: os.Getenv, Statfs: statfs, Addrs: interfaceAddrs, root: "/"}
+}
+
+// New zwraca system zakorzeniony w katalogu root (np. zamontowany obraz ratunkowy).
+// Pusty root albo "/" oznacza Host().
+func New(root string) *System {
+	if root == "" || root == "/" {
+		return Host()
+	}
+	return &System{FS: os.DirFS(root), Statfs: statfs, root: root}
+}
+
+// FromFS zwraca system oparty na dowolnym fs.FS, np. fstest.MapFS albo katalogu z fixture'ami.
+func FromFS(fsys fs.FS) *System {
+	return &System{FS: fsys}
+}
+
+// Root zwraca ścieżkę korzenia albo pusty napis, gdy system powstał z FromFS.
+func (s *System) Root() string {
+	return s.root
+}
+
+func rel(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+func (s *System) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(s.FS, rel(name))
+}
+
+func (s *System) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(s.FS, rel(name))
+}
+
+func (s *System) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(s.FS, rel(name))
+}
+
+// ReadLink zwraca cel dowiązania symbolicznego bez rozwiązywania go względem korzenia
+// (np. "/usr/bin/zsh" dla /proc/<pid>/exe). System z FromFS obsługuje dowiązania tylko wtedy,
+// gdy jego fs.FS ma metodę ReadLink.
+func (s *System) ReadLink(name string) (string, error) {
+	if s.root != "" {
+		return os.Readlink(filepath.Join(s.root, rel(name)))
+	}
+	if fsys, ok := s.FS.(interface{ ReadLink(string) (string, error) }); ok {
+		return fsys.ReadLink(rel(name))
+	}
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: errors.ErrUnsupported}
+}
+
+func (s *System) Exists(name string) bool {
+	_, err := s.Stat(name)
+	return err == nil
+}
+
+// WithRunner zwraca kopię systemu z innym wykonawcą poleceń (np. Recorder albo Replayer).
+func (s *System) WithRunner(r Runner) *System {
+	c := *s
+	c.Runner = r
+	return &c
+}
+
+// WithEnv zwraca kopię systemu z innym źródłem zmiennych środowiskowych (np. mapą w testach).
+func (s *System) WithEnv(env func(key string) string) *System {
+	c := *s
+	c.Env = env
+	return &c
+}
+
+// Getenv zwraca zmienną środowiskową badanej sesji albo pusty napis, gdy środowisko jest nieznane.
+func (s *System) Getenv(key string) string {
+	if s.Env == nil {
+		return ""
+	}
+	return s.Env(key)
+}
+
+// WithStatfs zwraca kopię systemu z innym źródłem pojemności systemów plików (np. mapą w testach).
+func (s *System) WithStatfs(statfs func(path string) (DiskSpace, error)) *System {
+	c := *s
+	c.Statfs = statfs
+	return &c
+}
+
+// DiskSpace zwraca pojemność systemu plików zamontowanego w mountpoint badanego systemu.
+func (s *System) DiskSpace(mountpoint string) (DiskSpace, error) {
+	if s.Statfs == nil {
+		return DiskSpace{}, ErrNoStatfs
+	}
+	return s.Statfs(filepath.Join(s.root, mountpoint))
+}
+
+// WithAddrs zwraca kopię systemu z innym źródłem adresów interfejsów (np. mapą w testach).
+func (s *System) WithAddrs(addrs func(iface string) ([]string, error)) *System {
+	c := *s
+	c.Addrs = addrs
+	return &c
+}
+
+// InterfaceAddrs zwraca adresy interfejsu w notacji CIDR albo nil, gdy adresy są nieznane.
+func (s *System) InterfaceAddrs(iface string) ([]string, error) {
+	if s.Addrs == nil {
+		return nil, nil
+	}
+	return s.Addrs(iface)
+}
+
+// WithCacheDir zwraca kopię systemu, którego sondy zapisują pliki podręczne w dir.
+func (s *System) WithCacheDir(dir string) *System {
+	c := *s
+	c.CacheDir = dir
+	return &c
+}
+
+// Output uruchamia zewnętrzny program i zwraca jego standardowe wyjście.
+func (s *System) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if s.Runner == nil {
+		return nil, ErrNoCommands
+	}
+	return s.Runner.Run(ctx, name, args...)
+}
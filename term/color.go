@@ -0,0 +1,61 @@
+// Package term zawiera to, co asfetch musi wiedzieć o terminalu, do którego pisze.
+package term
+
+import (
+	"fmt"
+	"os"
+)
+
+// ColorMode to tryb kolorowania wyjścia z opcji --color.
+type ColorMode int
+
+const (
+	// ColorAuto koloruje tylko wtedy, gdy wyjście jest terminalem, a NO_COLOR i TERM=dumb tego nie zabraniają.
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+func ParseColorMode(s string) (ColorMode, error) {
+	switch s {
+	case "auto":
+		return ColorAuto, nil
+	case "always":
+		return ColorAlways, nil
+	case "never":
+		return ColorNever, nil
+	}
+	return ColorAuto, fmt.Errorf("nieznany tryb kolorów %q (dostępne: auto, always, never)", s)
+}
+
+func (m ColorMode) String() string {
+	switch m {
+	case ColorAlways:
+		return "always"
+	case ColorNever:
+		return "never"
+	}
+	return "auto"
+}
+
+// Enabled mówi, czy pisząc do out należy używać kolorów.
+func (m ColorMode) Enabled(out *os.File) bool {
+	return m.enabled(os.Getenv, IsTerminal(out.Fd()))
+}
+
+func (m ColorMode) enabled(getenv func(string) string, tty bool) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	// https://no-color.org: liczy się każda niepusta wartość.
+	if getenv("NO_COLOR") != "" {
+		return false
+	}
+	if getenv("TERM") == "dumb" {
+		return false
+	}
+	return tty
+}
@@ -0,0 +1,55 @@
+package term
+
+import (
+	"os"
+	"testing"
+)
+
+func TestColorModeEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		mode ColorMode
+		env  map[string]string
+		tty  bool
+		want bool
+	}{
+		{"auto na terminalu", ColorAuto, nil, true, true},
+		{"auto do pliku", ColorAuto, nil, false, false},
+		{"NO_COLOR", ColorAuto, map[string]string{"NO_COLOR": "1"}, true, false},
+		{"pusty NO_COLOR", ColorAuto, map[string]string{"NO_COLOR": ""}, true, true},
+		{"TERM=dumb", ColorAuto, map[string]string{"TERM": "dumb"}, true, false},
+		{"always do pliku", ColorAlways, map[string]string{"NO_COLOR": "1"}, false, true},
+		{"never na terminalu", ColorNever, nil, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := tt.mode.enabled(getenv, tt.tty); got != tt.want {
+				t.Errorf("enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseColorMode(t *testing.T) {
+	for _, s := range []string{"auto", "always", "never"} {
+		m, err := ParseColorMode(s)
+		if err != nil || m.String() != s {
+			t.Errorf("ParseColorMode(%q) = %v, %v", s, m, err)
+		}
+	}
+	if _, err := ParseColorMode("yes"); err == nil {
+		t.Error("oczekiwano błędu")
+	}
+}
+
+func TestIsTerminalFile(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if IsTerminal(f.Fd()) {
+		t.Error("zwykły plik nie jest terminalem")
+	}
+}
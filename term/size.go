@@ -0,0 +1,21 @@
+package term
+
+// Winsize to rozmiar terminalu. XPixel i YPixel są zerowe, gdy terminal ich nie podaje.
+type Winsize struct {
+	Cols, Rows     int
+	XPixel, YPixel int
+}
+
+// Domyślny rozmiar komórki, gdy terminal nie podaje rozmiaru w pikselach.
+const (
+	DefaultCellWidth  = 8
+	DefaultCellHeight = 16
+)
+
+// CellSize zwraca rozmiar jednej komórki w pikselach.
+func (w Winsize) CellSize() (width, height int) {
+	if w.Cols <= 0 || w.Rows <= 0 || w.XPixel <= 0 || w.YPixel <= 0 {
+		return DefaultCellWidth, DefaultCellHeight
+	}
+	return w.XPixel / w.Cols, w.YPixel / w.Rows
+}
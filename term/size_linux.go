@@ -0,0 +1,15 @@
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Size zwraca rozmiar terminalu w komórkach i pikselach (TIOCGWINSZ).
+func Size(fd uintptr) (Winsize, error) {
+	var ws struct{ Row, Col, XPixel, YPixel uint16 }
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws))); errno != 0 {
+		return Winsize{}, errno
+	}
+	return Winsize{Cols: int(ws.Col), Rows: int(ws.Row), XPixel: int(ws.XPixel), YPixel: int(ws.YPixel)}, nil
+}
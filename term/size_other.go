@@ -0,0 +1,10 @@
+//go:build !linux
+
+package term
+
+import "errors"
+
+// Size poza Linuksem nie jest obsługiwany.
+func Size(fd uintptr) (Winsize, error) {
+	return Winsize{}, errors.New("rozmiar terminalu nieobsługiwany na tym systemie")
+}
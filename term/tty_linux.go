@@ -0,0 +1,13 @@
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// IsTerminal mówi, czy deskryptor jest terminalem (czy obsługuje TCGETS).
+func IsTerminal(fd uintptr) bool {
+	var t syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCGETS, uintptr(unsafe.Pointer(&t)))
+	return errno == 0
+}
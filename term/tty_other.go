@@ -0,0 +1,11 @@
+//go:build !linux
+
+package term
+
+import "os"
+
+// IsTerminal mówi, czy deskryptor jest urządzeniem znakowym; poza Linuksem to tylko przybliżenie.
+func IsTerminal(fd uintptr) bool {
+	info, err := os.NewFile(fd, "").Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,190 @@
+package term
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Width zwraca liczbę kolumn, które tekst zajmie w terminalu. Sekwencje ANSI (CSI, OSC i inne
+// ucieczki) nie zajmują miejsca, znaki szerokie wg Unicode East Asian Width (w tym emoji
+// z domyślną prezentacją emoji) zajmują dwie kolumny, a znaki łączące, formatujące
+// i sterujące – zero.
+func Width(s string) int {
+	width := 0
+	prev := 0
+	afterZWJ := false
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			i = skipEscape(s, i)
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+
+		switch {
+		case r == 0xFE0F:
+			// Selektor prezentacji emoji poszerza poprzedni znak o domyślnej prezentacji tekstowej.
+			if prev == 1 {
+				width++
+				prev = 2
+			}
+			continue
+		case r == 0x200D:
+			afterZWJ = true
+			continue
+		case afterZWJ:
+			// Sekwencje emoji łączone ZWJ terminal rysuje jako jeden znak.
+			afterZWJ = false
+			continue
+		}
+
+		w := RuneWidth(r)
+		if w > 0 {
+			prev = w
+		}
+		width += w
+	}
+	return width
+}
+
+// RuneWidth zwraca szerokość pojedynczego znaku: 0, 1 albo 2 kolumny.
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0:
+		return 0
+	case r < 0x20 || (r >= 0x7F && r < 0xA0):
+		return 0
+	case r < 0x300:
+		return 1
+	case r >= 0x1160 && r <= 0x11FF:
+		// Samogłoski i spółgłoski końcowe Hangul Jamo łączą się z poprzednią sylabą.
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case inTable(r, wideRanges):
+		return 2
+	}
+	return 1
+}
+
+// StripANSI usuwa z tekstu sekwencje ucieczki ANSI.
+func StripANSI(s string) string {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			i = skipEscape(s, i)
+			continue
+		}
+		out = append(out, s[i])
+		i++
+	}
+	return string(out)
+}
+
+// skipEscape zwraca indeks pierwszego bajtu po sekwencji ucieczki zaczynającej się w s[i].
+func skipEscape(s string, i int) int {
+	i++
+	if i >= len(s) {
+		return i
+	}
+	switch s[i] {
+	case '[':
+		// CSI: parametry i bajty pośrednie, zakończone bajtem z zakresu 0x40–0x7E.
+		for i++; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7E {
+				return i + 1
+			}
+		}
+		return i
+	case ']', 'P', '_', '^':
+		// OSC, DCS, APC, PM: aż do BEL albo ST (ESC \).
+		for i++; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return i
+	}
+	return i + 1
+}
+
+func inTable(r rune, table [][2]rune) bool {
+	i := sort.Search(len(table), func(i int) bool { return table[i][1] >= r })
+	return i < len(table) && table[i][0] <= r
+}
+
+// Truncate skraca tekst do width kolumn, kończąc go wielokropkiem, jeśli się nie mieści.
+// Sekwencje ANSI są zachowywane i nie liczą się do szerokości.
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	return cut(s, width-1) + "…"
+}
+
+// Wrap dzieli tekst na linie o szerokości co najwyżej width kolumn, łamiąc na spacjach,
+// a zbyt długie słowa – w dowolnym miejscu.
+func Wrap(s string, width int) []string {
+	if width <= 0 || Width(s) <= width {
+		return []string{s}
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		switch {
+		case line == "" && Width(word) <= width:
+			line = word
+		case line != "" && Width(line)+1+Width(word) <= width:
+			line += " " + word
+		default:
+			if line != "" {
+				lines = append(lines, line)
+			}
+			for Width(word) > width {
+				part := cut(word, width)
+				if part == "" {
+					// Znak szerszy niż cała linia (np. CJK przy width 1) dostaje własną linię,
+					// inaczej pętla nigdy by się nie skończyła.
+					_, size := utf8.DecodeRuneInString(word)
+					part = word[:size]
+				}
+				lines = append(lines, part)
+				word = word[len(part):]
+			}
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// cut zwraca najdłuższy prefiks s o szerokości co najwyżej width kolumn.
+func cut(s string, width int) string {
+	w := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			i = skipEscape(s, i)
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		rw := RuneWidth(r)
+		if w+rw > width {
+			return s[:i]
+		}
+		w += rw
+		i += size
+	}
+	return s
+}
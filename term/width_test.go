@@ -0,0 +1,97 @@
+package term
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"asfetch", 7},
+		{"Zażółć gęślą jaźń", 17},
+		{"⠀⣠⡀⠿", 4},            // Braille ma szerokość neutralną
+		{"─│╭╮", 4},            // ramki
+		{"▀▄█", 3},             // bloki
+		{"日本語", 6},             // CJK
+		{"ｱｲｳ", 3},             // katakana połówkowej szerokości
+		{"ＡＢ", 4},              // pełna szerokość
+		{"😀🦊", 4},              // emoji spoza starego zakresu U+1F600–1F64F też są szerokie
+		{"❤", 1},               // domyślna prezentacja tekstowa
+		{"❤\uFE0F", 2},         // z selektorem prezentacji emoji
+		{"👨\u200D👩\u200D👧", 2}, // sekwencja ZWJ
+		{"e\u0301", 1},         // znak łączący
+		{"\033[96mtux\033[0m", 3},
+		{"\033[38;2;137;180;250m日\033[0m", 2},
+		{"\033]8;;https://example.com\033\\link\033]8;;\033\\", 4},
+		{"a\tb", 2},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.s); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	if got := StripANSI("\033[1;31mczerwony\033[0m i \033]0;tytuł\a zwykły"); got != "czerwony i  zwykły" {
+		t.Errorf("StripANSI = %q", got)
+	}
+}
+
+func TestWideRangesSorted(t *testing.T) {
+	for i, r := range wideRanges {
+		if r[0] > r[1] || (i > 0 && wideRanges[i-1][1] >= r[0]) {
+			t.Fatalf("zakres %d (%#x–%#x) nieposortowany albo nachodzi na poprzedni", i, r[0], r[1])
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"Radeon RX 6800", 20, "Radeon RX 6800"},
+		{"Radeon RX 6800", 14, "Radeon RX 6800"},
+		{"Radeon RX 6800", 10, "Radeon RX…"},
+		{"日本語テキスト", 7, "日本語…"},
+		{"日本語テキスト", 6, "日本…"},
+		{"\033[31mczerwony\033[0m", 5, "\033[31mczer…"},
+		{"abc", 0, ""},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if tt.width > 0 && Width(got) > tt.width {
+			t.Errorf("Truncate(%q, %d) ma szerokość %d", tt.s, tt.width, Width(got))
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  []string
+	}{
+		{"krótki", 10, []string{"krótki"}},
+		{"AMD Radeon RX 6800 XT", 10, []string{"AMD Radeon", "RX 6800 XT"}},
+		{"Navi21XTXHgigantyczna nazwa", 8, []string{"Navi21XT", "XHgigant", "yczna", "nazwa"}},
+		{"日本語 テキスト", 6, []string{"日本語", "テキス", "ト"}},
+		{"日本語", 1, []string{"日", "本", "語"}},
+		{"a日b", 1, []string{"a", "日", "b"}},
+	}
+	for _, tt := range tests {
+		got := Wrap(tt.s, tt.width)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Wrap(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
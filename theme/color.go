@@ -0,0 +1,73 @@
+package theme
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// namedColors to kody SGR kolorów z podstawowej 16-kolorowej palety terminala.
+var namedColors = map[string]int{
+	"black":          30,
+	"red":            31,
+	"green":          32,
+	"yellow":         33,
+	"blue":           34,
+	"magenta":        35,
+	"cyan":           36,
+	"white":          37,
+	"bright_black":   90,
+	"bright_red":     91,
+	"bright_green":   92,
+	"bright_yellow":  93,
+	"bright_blue":    94,
+	"bright_magenta": 95,
+	"bright_cyan":    96,
+	"bright_white":   97,
+}
+
+// ParseColor zamienia opis koloru na sekwencję ANSI ustawiającą kolor tekstu. Obsługiwane są:
+// nazwy z palety 16 kolorów ("cyan", "bright_blue"), numery z palety 256 kolorów ("208")
+// oraz kolory 24-bitowe w zapisie szesnastkowym ("#89b4fa" albo "#fff").
+// Pusty opis oznacza brak koloru.
+func ParseColor(spec string) (string, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return "", nil
+	}
+
+	if hex, ok := strings.CutPrefix(spec, "#"); ok {
+		r, g, b, err := parseHex(hex)
+		if err != nil {
+			return "", fmt.Errorf("niepoprawny kolor %q: %w", spec, err)
+		}
+		return fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b), nil
+	}
+
+	if n, err := strconv.Atoi(spec); err == nil {
+		if n < 0 || n > 255 {
+			return "", fmt.Errorf("niepoprawny kolor %q: numer spoza palety 0-255", spec)
+		}
+		return fmt.Sprintf("\033[38;5;%dm", n), nil
+	}
+
+	name := strings.ReplaceAll(strings.ToLower(spec), "-", "_")
+	if code, ok := namedColors[name]; ok {
+		return fmt.Sprintf("\033[%dm", code), nil
+	}
+	return "", fmt.Errorf("nieznany kolor %q", spec)
+}
+
+func parseHex(hex string) (r, g, b uint8, err error) {
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return 0, 0, 0, fmt.Errorf("oczekiwano #rgb albo #rrggbb")
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("oczekiwano cyfr szesnastkowych")
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), nil
+}
@@ -0,0 +1,179 @@
+// Package theme opisuje kolory tabeli asfetch: motywy (z konfiguracji, z plików albo wbudowane)
+// i paletę sekwencji ANSI, której używa renderer.
+package theme
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultName to motyw używany, gdy konfiguracja nie wskazuje innego.
+const DefaultName = "asf"
+
+// Theme przypisuje kolory rolom w tabeli. Wartości to opisy przyjmowane przez ParseColor.
+// Pola *Alt kolorują co drugi wiersz; puste oznaczają ten sam kolor co wiersze nieparzyste.
+// Puste pola dziedziczą z motywu Base (wbudowanego), jeśli jest ustawiony.
+type Theme struct {
+	Base         string `json:"base,omitempty"`
+	Label        string `json:"label,omitempty"`
+	LabelAlt     string `json:"label_alt,omitempty"`
+	Separator    string `json:"separator,omitempty"`
+	SeparatorAlt string `json:"separator_alt,omitempty"`
+	Value        string `json:"value,omitempty"`
+	ValueAlt     string `json:"value_alt,omitempty"`
+	Logo         string `json:"logo,omitempty"`
+	// Title koloruje wartość modułu user (user@host).
+	Title string `json:"title,omitempty"`
+}
+
+// Palette to motyw zamieniony na gotowe sekwencje ANSI. Zerowa Palette oznacza wyjście bez kolorów.
+type Palette struct {
+	Label        string
+	LabelAlt     string
+	Separator    string
+	SeparatorAlt string
+	Value        string
+	ValueAlt     string
+	Logo         string
+	Title        string
+	Reset        string
+}
+
+// Enabled mówi, czy paleta w ogóle wypisuje kolory.
+func (p Palette) Enabled() bool {
+	return p.Reset != ""
+}
+
+//go:embed themes/*.json
+var bundled embed.FS
+
+// Bundled zwraca wbudowany motyw o podanej nazwie.
+func Bundled(name string) (Theme, error) {
+	data, err := bundled.ReadFile(path.Join("themes", name+".json"))
+	if err != nil {
+		return Theme{}, fmt.Errorf("nieznany motyw %q (wbudowane: %s)", name, strings.Join(Names(), ", "))
+	}
+	return parse(data)
+}
+
+// Names zwraca nazwy wbudowanych motywów.
+func Names() []string {
+	files, _ := fs.Glob(bundled, "themes/*.json")
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, strings.TrimSuffix(path.Base(file), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Default zwraca domyślny motyw.
+func Default() Theme {
+	t, err := Bundled(DefaultName)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// LoadFile wczytuje motyw z pliku JSON.
+func LoadFile(filename string) (Theme, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Theme{}, fmt.Errorf("nie udało się wczytać motywu z pliku %s: %w", filename, err)
+	}
+	t, err := parse(data)
+	if err != nil {
+		return Theme{}, fmt.Errorf("niepoprawny motyw w pliku %s: %w", filename, err)
+	}
+	return t, nil
+}
+
+func parse(data []byte) (Theme, error) {
+	var t Theme
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Theme{}, err
+	}
+	return t, nil
+}
+
+// Resolve uzupełnia puste pola motywu kolorami z motywu bazowego.
+func (t Theme) Resolve() (Theme, error) {
+	if t.Base == "" {
+		return t, nil
+	}
+	base, err := Bundled(t.Base)
+	if err != nil {
+		return Theme{}, err
+	}
+	base, err = base.Resolve()
+	if err != nil {
+		return Theme{}, err
+	}
+
+	fields := []struct{ dst, src *string }{
+		{&t.Label, &base.Label},
+		{&t.LabelAlt, &base.LabelAlt},
+		{&t.Separator, &base.Separator},
+		{&t.SeparatorAlt, &base.SeparatorAlt},
+		{&t.Value, &base.Value},
+		{&t.ValueAlt, &base.ValueAlt},
+		{&t.Logo, &base.Logo},
+		{&t.Title, &base.Title},
+	}
+	for _, f := range fields {
+		if *f.dst == "" {
+			*f.dst = *f.src
+		}
+	}
+	t.Base = ""
+	return t, nil
+}
+
+// Palette zamienia motyw na sekwencje ANSI.
+func (t Theme) Palette() (Palette, error) {
+	t, err := t.Resolve()
+	if err != nil {
+		return Palette{}, err
+	}
+
+	p := Palette{Reset: "\033[0m"}
+	roles := []struct {
+		name string
+		spec string
+		dst  *string
+	}{
+		{"label", t.Label, &p.Label},
+		{"label_alt", t.LabelAlt, &p.LabelAlt},
+		{"separator", t.Separator, &p.Separator},
+		{"separator_alt", t.SeparatorAlt, &p.SeparatorAlt},
+		{"value", t.Value, &p.Value},
+		{"value_alt", t.ValueAlt, &p.ValueAlt},
+		{"logo", t.Logo, &p.Logo},
+		{"title", t.Title, &p.Title},
+	}
+	for _, role := range roles {
+		code, err := ParseColor(role.spec)
+		if err != nil {
+			return Palette{}, fmt.Errorf("%s: %w", role.name, err)
+		}
+		*role.dst = code
+	}
+
+	if t.LabelAlt == "" {
+		p.LabelAlt = p.Label
+	}
+	if t.SeparatorAlt == "" {
+		p.SeparatorAlt = p.Separator
+	}
+	if t.ValueAlt == "" {
+		p.ValueAlt = p.Value
+	}
+	return p, nil
+}
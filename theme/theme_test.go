@@ -0,0 +1,108 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"", ""},
+		{"cyan", "\033[36m"},
+		{"Bright-Blue", "\033[94m"},
+		{"208", "\033[38;5;208m"},
+		{"#89b4fa", "\033[38;2;137;180;250m"},
+		{"#fff", "\033[38;2;255;255;255m"},
+	}
+	for _, tt := range tests {
+		got, err := ParseColor(tt.spec)
+		if err != nil {
+			t.Errorf("ParseColor(%q): %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseColor(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"purple", "256", "-1", "#12345", "#ggg"} {
+		if _, err := ParseColor(spec); err == nil {
+			t.Errorf("ParseColor(%q): oczekiwano błędu", spec)
+		}
+	}
+}
+
+func TestBundledThemesParse(t *testing.T) {
+	names := Names()
+	if len(names) < 2 {
+		t.Fatalf("Names() = %v", names)
+	}
+	for _, name := range names {
+		th, err := Bundled(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		pal, err := th.Palette()
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if !pal.Enabled() || pal.Label == "" || pal.Value == "" {
+			t.Errorf("%s: niepełna paleta %+v", name, pal)
+		}
+	}
+}
+
+func TestPaletteAltFallback(t *testing.T) {
+	pal, err := Theme{Label: "red", Value: "#000000"}.Palette()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pal.LabelAlt != pal.Label || pal.ValueAlt != pal.Value || pal.SeparatorAlt != "" {
+		t.Errorf("kolory *Alt powinny powtarzać podstawowe: %+v", pal)
+	}
+}
+
+func TestResolveBase(t *testing.T) {
+	pal, err := Theme{Base: "nord", Label: "red"}.Palette()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nord, _ := Bundled("nord")
+	want, _ := nord.Palette()
+	if pal.Label != "\033[31m" {
+		t.Errorf("Label = %q", pal.Label)
+	}
+	if pal.Value != want.Value || pal.Logo != want.Logo {
+		t.Errorf("puste pola nie odziedziczyły kolorów z nord: %+v", pal)
+	}
+
+	if _, err := (Theme{Base: "nieistniejacy"}).Palette(); err == nil {
+		t.Error("oczekiwano błędu dla nieznanego motywu bazowego")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "moj.json")
+	if err := os.WriteFile(file, []byte(`{"label": "#ff0000", "value": "15"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	th, err := LoadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Label != "#ff0000" || th.Value != "15" {
+		t.Errorf("LoadFile = %+v", th)
+	}
+
+	if err := os.WriteFile(file, []byte(`{"label": `), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(file); err == nil {
+		t.Error("oczekiwano błędu dla uszkodzonego pliku")
+	}
+}